@@ -0,0 +1,29 @@
+## The following code removes build cache older than a week from the `ci` builder after each build.
+
+resource "docker_buildx_build" "app" {
+  context = "${path.cwd}/app"
+  builder = "ci"
+
+  output {
+    type = "local"
+    dest = "${path.cwd}/dist"
+  }
+
+  lifecycle {
+    action_trigger {
+      events  = [after_create]
+      actions = [action.docker_builder_prune.ci]
+    }
+  }
+}
+
+action "docker_builder_prune" "ci" {
+  config {
+    builder      = "ci"
+    all          = true
+    keep_storage = "10GB"
+    filter = [
+      "until=168h",
+    ]
+  }
+}
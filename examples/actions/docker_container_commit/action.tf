@@ -0,0 +1,28 @@
+resource "docker_image" "busybox" {
+  name = "busybox:1.35.0"
+}
+
+resource "docker_container" "target" {
+  name     = "docker-container-commit-example"
+  image    = docker_image.busybox.image_id
+  must_run = true
+  command  = ["sh", "-c", "sleep 300"]
+
+  lifecycle {
+    action_trigger {
+      events  = [after_update]
+      actions = [action.docker_container_commit.snapshot]
+    }
+  }
+}
+
+action "docker_container_commit" "snapshot" {
+  config {
+    container = docker_container.target.name
+    reference = "docker-container-commit-example:debug"
+    author    = "Jane Doe <jane@example.com>"
+    message   = "snapshot of the debugging state"
+    pause     = true
+    changes   = ["CMD [\"sh\"]", "ENV DEBUG=1"]
+  }
+}
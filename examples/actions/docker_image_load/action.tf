@@ -1,6 +1,6 @@
 resource "terraform_data" "load_trigger" {
   triggers_replace = [
-    filesha512("./busybox-image.tar")
+    filesha512("./busybox-image.tar.zst")
   ]
 
   lifecycle {
@@ -13,7 +13,7 @@ resource "terraform_data" "load_trigger" {
 
 action "docker_image_load" "load" {
   config {
-    source   = pathexpand("./busybox-image.tar")
+    source   = pathexpand("./busybox-image.tar.zst")
     quiet    = true
     platform = "linux/amd64"
   }
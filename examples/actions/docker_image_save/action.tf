@@ -15,8 +15,9 @@ resource "terraform_data" "save_trigger" {
 
 action "docker_image_save" "save" {
   config {
-    images   = [docker_image.busybox.name]
-    output   = pathexpand("./busybox-image.tar")
-    platform = "linux/amd64"
+    images      = [docker_image.busybox.name]
+    output      = pathexpand("./busybox-image.tar.zst")
+    platform    = "linux/amd64"
+    compression = "zstd"
   }
 }
@@ -0,0 +1,8 @@
+data "docker_build_cache" "ci" {
+  builder = "ci"
+  filter  = ["type=exec.cachemount"]
+}
+
+output "ci_cache_size" {
+  value = data.docker_build_cache.ci.total_size
+}
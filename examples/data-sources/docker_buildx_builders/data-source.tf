@@ -0,0 +1,11 @@
+data "docker_buildx_builders" "all" {}
+
+locals {
+  remote_builders = [
+    for builder in data.docker_buildx_builders.all.builders : builder.name
+    if builder.driver == "remote" && builder.status == "running"
+  ]
+
+  # use the remote builder if it is bootstrapped, otherwise the default one
+  builder = length(local.remote_builders) > 0 ? local.remote_builders[0] : data.docker_buildx_builders.all.resolved_builder
+}
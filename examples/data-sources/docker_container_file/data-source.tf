@@ -0,0 +1,10 @@
+data "docker_container_file" "admin_password" {
+  container = docker_container.grafana.name
+  path      = "/var/lib/grafana/admin-password"
+  sensitive = true
+}
+
+output "grafana_admin_password" {
+  value     = data.docker_container_file.admin_password.sensitive_content
+  sensitive = true
+}
@@ -0,0 +1,4 @@
+data "docker_dockerfile_check" "app" {
+  context          = "${path.module}/app"
+  fail_on_warnings = true
+}
@@ -17,3 +17,9 @@ data "docker_image" "digest" {
 data "docker_image" "tag_and_digest" {
   name = "nginx:1.19.1@sha256:36b74457bccb56fbf8b05f79c85569501b721d4db813b684391d63e02287c0b2"
 }
+
+# inspects the arm64 variant of a multi-platform image
+data "docker_image" "arm64" {
+  name     = "nginx:1.19.1"
+  platform = "linux/arm64"
+}
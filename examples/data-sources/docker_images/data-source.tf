@@ -0,0 +1,9 @@
+data "docker_images" "busybox" {
+  reference = ["busybox:*"]
+  dangling  = false
+}
+
+output "busybox_tags" {
+  description = "All local busybox tags"
+  value       = flatten([for i in data.docker_images.busybox.images : i.repo_tags])
+}
@@ -0,0 +1,23 @@
+resource "docker_buildx_bake" "services" {
+  files   = ["${path.cwd}/docker-bake.hcl"]
+  targets = ["default"]
+  push    = true
+
+  set = [
+    "*.platform=linux/amd64,linux/arm64",
+  ]
+
+  variables = {
+    TAG = var.release
+  }
+}
+
+resource "docker_service" "api" {
+  name = "api"
+
+  task_spec {
+    container_spec {
+      image = "registry.example.com/api@${docker_buildx_bake.services.image_digests["api"]}"
+    }
+  }
+}
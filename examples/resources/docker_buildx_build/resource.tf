@@ -0,0 +1,9 @@
+resource "docker_buildx_build" "binaries" {
+  context = "${path.cwd}/app"
+  target  = "artifact"
+
+  output {
+    type = "local"
+    dest = "${path.cwd}/dist"
+  }
+}
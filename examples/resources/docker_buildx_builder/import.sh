@@ -0,0 +1,2 @@
+#!/bin/bash
+terraform import docker_buildx_builder.foo builder-name
@@ -0,0 +1,9 @@
+resource "docker_image" "app" {
+  name = "registry.example.com/team/app:1.4.2"
+
+  source_archive {
+    path     = "${path.module}/images/app-1.4.2.tar"
+    platform = "linux/amd64"
+    digest   = "sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741"
+  }
+}
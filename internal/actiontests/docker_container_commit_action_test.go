@@ -0,0 +1,88 @@
+package actiontests
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestDockerContainerCommitAction_commitsContainerChanges(t *testing.T) {
+	preCheckDocker(t)
+
+	containerName := fmt.Sprintf("tf-acc-docker-container-commit-%d", time.Now().UnixNano())
+	imageRef := fmt.Sprintf("tf-acc-docker-committed-%d:debug", time.Now().UnixNano())
+	defer func() {
+		_ = exec.Command("docker", "image", "rm", "-f", imageRef).Run()
+	}()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "docker_image" "busybox" {
+  name         = "busybox:1.35.0"
+  keep_locally = true
+}
+
+resource "docker_container" "target" {
+  name     = %q
+  image    = docker_image.busybox.image_id
+  must_run = true
+  command  = ["sh", "-c", "sleep 300"]
+
+  upload {
+    content = "committed"
+    file    = "/tmp/container_commit_action_file"
+  }
+
+  lifecycle {
+    action_trigger {
+      events  = [after_create]
+      actions = [action.docker_container_commit.commit_container]
+    }
+  }
+}
+
+action "docker_container_commit" "commit_container" {
+  config {
+    container = docker_container.target.name
+    reference = %q
+    author    = "Terraform Acceptance Tests"
+    message   = "committed by the acceptance tests"
+    changes   = ["ENV COMMITTED=true"]
+  }
+}
+`, containerName, imageRef),
+				PostApplyFunc: func() {
+					inspectCmd := exec.Command("docker", "image", "inspect", "--format", "{{.Author}}|{{.Comment}}|{{json .Config.Env}}", imageRef)
+					output, err := inspectCmd.CombinedOutput()
+					if err != nil {
+						t.Fatalf("expected committed image %q to exist: %s: %s", imageRef, err, string(output))
+					}
+
+					inspected := strings.TrimSpace(string(output))
+					if !strings.HasPrefix(inspected, "Terraform Acceptance Tests|committed by the acceptance tests|") {
+						t.Fatalf("expected author and message to be set on %q, got %q", imageRef, inspected)
+					}
+					if !strings.Contains(inspected, "COMMITTED=true") {
+						t.Fatalf("expected the changes to be applied to %q, got %q", imageRef, inspected)
+					}
+
+					runCmd := exec.Command("docker", "run", "--rm", imageRef, "sh", "-c", "test -f /tmp/container_commit_action_file")
+					if output, err := runCmd.CombinedOutput(); err != nil {
+						t.Fatalf("expected committed image %q to contain the file of the container: %s: %s", imageRef, err, string(output))
+					}
+				},
+			},
+		},
+	})
+}
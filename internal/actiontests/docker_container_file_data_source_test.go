@@ -0,0 +1,61 @@
+package actiontests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestDockerContainerFileDataSource_readsGeneratedFile(t *testing.T) {
+	preCheckDocker(t)
+
+	containerName := fmt.Sprintf("tf-acc-docker-container-file-%d", time.Now().UnixNano())
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "docker_image" "busybox" {
+  name         = "busybox:1.35.0"
+  keep_locally = true
+}
+
+resource "docker_container" "target" {
+  name    = %q
+  image   = docker_image.busybox.image_id
+  command = ["sh", "-c", "printf hello > /tmp/token && chmod 600 /tmp/token && ln -s /tmp/token /tmp/token-link && sleep 300"]
+
+  readiness {
+    exec {
+      command = ["test", "-L", "/tmp/token-link"]
+    }
+  }
+}
+
+data "docker_container_file" "plain" {
+  container = docker_container.target.name
+  path      = "/tmp/token-link"
+}
+
+data "docker_container_file" "sensitive" {
+  container = docker_container.target.name
+  path      = "/tmp/token"
+  sensitive = true
+}
+`, containerName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.docker_container_file.plain", "content", "hello"),
+					resource.TestCheckResourceAttr("data.docker_container_file.plain", "content_base64", "aGVsbG8="),
+					resource.TestCheckResourceAttr("data.docker_container_file.plain", "sha256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"),
+					resource.TestCheckResourceAttr("data.docker_container_file.plain", "size", "5"),
+					resource.TestCheckResourceAttr("data.docker_container_file.plain", "mode", "0600"),
+					resource.TestCheckNoResourceAttr("data.docker_container_file.sensitive", "content"),
+					resource.TestCheckResourceAttr("data.docker_container_file.sensitive", "sensitive_content", "hello"),
+				),
+			},
+		},
+	})
+}
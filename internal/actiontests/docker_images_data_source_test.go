@@ -0,0 +1,69 @@
+package actiontests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestDockerImagesDataSource_filtersByReference(t *testing.T) {
+	preCheckDocker(t)
+
+	imageName := "busybox:1.35.0"
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: protoV6ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "docker_image" "busybox" {
+  name         = %q
+  keep_locally = true
+}
+
+data "docker_images" "this" {
+  reference = ["busybox:*"]
+  dangling  = false
+
+  depends_on = [docker_image.busybox]
+}
+`, imageName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.docker_images.this", "id", "docker_images"),
+					testCheckDockerImagesDataSourceContainsOnly("data.docker_images.this", imageName, "busybox:"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckDockerImagesDataSourceContainsOnly(resourceName string, imageName string, prefix string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %q not found in state", resourceName)
+		}
+
+		found := false
+		for key, value := range rs.Primary.Attributes {
+			if !strings.Contains(key, ".repo_tags.") || strings.HasSuffix(key, ".#") {
+				continue
+			}
+
+			if !strings.HasPrefix(value, prefix) {
+				return fmt.Errorf("expected only images matching %q in data source state, found %q", prefix, value)
+			}
+			if value == imageName {
+				found = true
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("image %q not found in data source state: %#v", imageName, rs.Primary.Attributes)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/go-units"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type DockerBuilderPruneAction struct {
+	providerConfig *ProviderConfig
+}
+
+type DockerBuilderPruneActionModel struct {
+	Builder     types.String `tfsdk:"builder"`
+	All         types.Bool   `tfsdk:"all"`
+	KeepStorage types.String `tfsdk:"keep_storage"`
+	Filter      types.List   `tfsdk:"filter"`
+}
+
+func (a *DockerBuilderPruneAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_builder_prune"
+}
+
+func (a *DockerBuilderPruneAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		MarkdownDescription: "Remove build cache, similar to `docker builder prune` or `docker buildx prune --builder` (without interactive confirmation).",
+		Attributes: map[string]actionschema.Attribute{
+			"builder": actionschema.StringAttribute{
+				MarkdownDescription: "The name of the buildx builder whose cache is removed. If empty, the build cache of the Docker daemon is removed.",
+				Optional:            true,
+			},
+			"all": actionschema.BoolAttribute{
+				MarkdownDescription: "Remove all unused build cache, not just dangling ones.",
+				Optional:            true,
+			},
+			"keep_storage": actionschema.StringAttribute{
+				MarkdownDescription: "Amount of disk space to keep for cache, e.g. `10GB`.",
+				Optional:            true,
+			},
+			"filter": actionschema.ListAttribute{
+				MarkdownDescription: "Provide filter values in `key=value` format, e.g. `until=24h` or `type=regular`. Can be specified multiple times.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (a *DockerBuilderPruneAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	a.providerConfig = providerConfig
+}
+
+func (a *DockerBuilderPruneAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	if a.providerConfig == nil {
+		resp.Diagnostics.AddError("Provider not configured", "The provider configuration is unavailable for docker builder prune action invocation.")
+		return
+	}
+
+	var config DockerBuilderPruneActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filterExpressions []string
+	if !config.Filter.IsNull() && !config.Filter.IsUnknown() {
+		resp.Diagnostics.Append(config.Filter.ElementsAs(ctx, &filterExpressions, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	pruneFilters, err := parseSystemPruneFilterExpressions(filterExpressions)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid filter", err.Error())
+		return
+	}
+
+	options := buildCachePruneOptions{
+		all:     config.All.ValueBool(),
+		filters: pruneFilters,
+	}
+	if keepStorage := config.KeepStorage.ValueString(); keepStorage != "" {
+		options.keepStorage, err = units.RAMInBytes(keepStorage)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid keep_storage", fmt.Sprintf("Unable to parse keep_storage %q: %s", keepStorage, err))
+			return
+		}
+	}
+
+	client, err := a.providerConfig.MakeClient(ctx, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker client error", fmt.Sprintf("Unable to create Docker client: %s", err))
+		return
+	}
+
+	result, err := pruneBuildCache(ctx, client, config.Builder.ValueString(), options)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker builder prune failed", err.Error())
+		return
+	}
+
+	if resp.SendProgress != nil {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf(
+				"pruned_build_cache_entries=%d space_reclaimed_bytes=%d",
+				result.recordsDeleted,
+				result.spaceReclaimed,
+			),
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestToBuildkitPruneInfo(t *testing.T) {
+	t.Run("until and filters", func(t *testing.T) {
+		pruneFilters, err := parseSystemPruneFilterExpressions([]string{
+			"until=24h",
+			"type=regular",
+			"id=abc",
+		})
+		if err != nil {
+			t.Fatalf("parseSystemPruneFilterExpressions returned error: %s", err)
+		}
+
+		pruneInfo, err := toBuildkitPruneInfo(pruneFilters)
+		if err != nil {
+			t.Fatalf("toBuildkitPruneInfo returned error: %s", err)
+		}
+		if pruneInfo.KeepDuration != 24*time.Hour {
+			t.Fatalf("expected keep duration 24h, got %s", pruneInfo.KeepDuration)
+		}
+		expected := []string{"id~=abc,type==regular"}
+		if !reflect.DeepEqual(pruneInfo.Filter, expected) {
+			t.Fatalf("expected filter %#v, got %#v", expected, pruneInfo.Filter)
+		}
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		pruneFilters, err := parseSystemPruneFilterExpressions([]string{"until=yesterday"})
+		if err != nil {
+			t.Fatalf("parseSystemPruneFilterExpressions returned error: %s", err)
+		}
+		if _, err := toBuildkitPruneInfo(pruneFilters); err == nil {
+			t.Fatalf("expected error for invalid until filter")
+		}
+	})
+
+	t.Run("conflicting filters", func(t *testing.T) {
+		pruneFilters, err := parseSystemPruneFilterExpressions([]string{"until=24h", "unused-for=12h"})
+		if err != nil {
+			t.Fatalf("parseSystemPruneFilterExpressions returned error: %s", err)
+		}
+		if _, err := toBuildkitPruneInfo(pruneFilters); err == nil {
+			t.Fatalf("expected error for conflicting until and unused-for filters")
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type DockerContainerCommitAction struct {
+	providerConfig *ProviderConfig
+}
+
+type DockerContainerCommitActionModel struct {
+	Container types.String `tfsdk:"container"`
+	Reference types.String `tfsdk:"reference"`
+	Author    types.String `tfsdk:"author"`
+	Message   types.String `tfsdk:"message"`
+	Pause     types.Bool   `tfsdk:"pause"`
+	Changes   types.List   `tfsdk:"changes"`
+}
+
+func (a *DockerContainerCommitAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container_commit"
+}
+
+func (a *DockerContainerCommitAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = actionschema.Schema{
+		MarkdownDescription: "Create a new image from the changes of a container, similar to `docker container commit`.",
+		Attributes: map[string]actionschema.Attribute{
+			"container": actionschema.StringAttribute{
+				MarkdownDescription: "Container name or ID to commit.",
+				Required:            true,
+			},
+			"reference": actionschema.StringAttribute{
+				MarkdownDescription: "Image name and optional tag to apply to the new image, for example `my-image:debug`. If not set, the new image is only available by its ID.",
+				Optional:            true,
+			},
+			"author": actionschema.StringAttribute{
+				MarkdownDescription: "Author of the new image, for example `Jane Doe <jane@example.com>`.",
+				Optional:            true,
+			},
+			"message": actionschema.StringAttribute{
+				MarkdownDescription: "Optional commit message to store with the new image.",
+				Optional:            true,
+			},
+			"pause": actionschema.BoolAttribute{
+				MarkdownDescription: "Pause the container during the commit. Defaults to `true`.",
+				Optional:            true,
+			},
+			"changes": actionschema.ListAttribute{
+				MarkdownDescription: "Raw Dockerfile instructions to apply to the new image, for example `CMD [\"sh\"]`.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (a *DockerContainerCommitAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	a.providerConfig = providerConfig
+}
+
+func (a *DockerContainerCommitAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	if a.providerConfig == nil {
+		resp.Diagnostics.AddError("Provider not configured", "The provider configuration is unavailable for docker container commit action invocation.")
+		return
+	}
+
+	var config DockerContainerCommitActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	containerName := strings.TrimSpace(config.Container.ValueString())
+	if config.Container.IsNull() || config.Container.IsUnknown() || containerName == "" {
+		resp.Diagnostics.AddError("Invalid container", "Attribute `container` must be a non-empty container name or ID.")
+		return
+	}
+
+	var changes []string
+	if !config.Changes.IsNull() && !config.Changes.IsUnknown() {
+		resp.Diagnostics.Append(config.Changes.ElementsAs(ctx, &changes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	dockerClient, err := a.providerConfig.MakeClient(ctx, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker client error", fmt.Sprintf("Unable to create Docker client: %s", err))
+		return
+	}
+
+	commitOptions := newContainerCommitOptions(config, changes)
+	commitResponse, err := dockerClient.ContainerCommit(ctx, containerName, commitOptions)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker container commit failed", err.Error())
+		return
+	}
+
+	if resp.SendProgress != nil {
+		message := fmt.Sprintf("committed_container=%s image_id=%s", containerName, commitResponse.ID)
+		if commitOptions.Reference != "" {
+			message += fmt.Sprintf(" reference=%s", commitOptions.Reference)
+		}
+		resp.SendProgress(action.InvokeProgressEvent{Message: message})
+	}
+}
+
+// newContainerCommitOptions returns the options to commit a container with, which pause the
+// container unless pause is set to false, just like `docker container commit`.
+func newContainerCommitOptions(config DockerContainerCommitActionModel, changes []string) container.CommitOptions {
+	pause := true
+	if !config.Pause.IsNull() && !config.Pause.IsUnknown() {
+		pause = config.Pause.ValueBool()
+	}
+
+	return container.CommitOptions{
+		Reference: strings.TrimSpace(config.Reference.ValueString()),
+		Comment:   config.Message.ValueString(),
+		Author:    config.Author.ValueString(),
+		Changes:   changes,
+		Pause:     pause,
+	}
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNewContainerCommitOptions(t *testing.T) {
+	changes := []string{"CMD [\"sh\"]", "ENV DEBUG=1"}
+
+	cases := []struct {
+		name     string
+		config   DockerContainerCommitActionModel
+		changes  []string
+		expected container.CommitOptions
+	}{
+		{
+			name: "defaults",
+			config: DockerContainerCommitActionModel{
+				Container: types.StringValue("app"),
+				Reference: types.StringNull(),
+				Author:    types.StringNull(),
+				Message:   types.StringNull(),
+				Pause:     types.BoolNull(),
+			},
+			expected: container.CommitOptions{Pause: true},
+		},
+		{
+			name: "all options",
+			config: DockerContainerCommitActionModel{
+				Container: types.StringValue("app"),
+				Reference: types.StringValue(" debug/app:snapshot "),
+				Author:    types.StringValue("Jane Doe <jane@example.com>"),
+				Message:   types.StringValue("debugging state"),
+				Pause:     types.BoolValue(false),
+			},
+			changes: changes,
+			expected: container.CommitOptions{
+				Reference: "debug/app:snapshot",
+				Comment:   "debugging state",
+				Author:    "Jane Doe <jane@example.com>",
+				Changes:   changes,
+				Pause:     false,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := newContainerCommitOptions(tc.config, tc.changes)
+			if !reflect.DeepEqual(options, tc.expected) {
+				t.Fatalf("expected %+v, got %+v", tc.expected, options)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/hashicorp/terraform-plugin-framework/action"
 	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
@@ -176,54 +177,84 @@ func (a *DockerExecAction) Invoke(ctx context.Context, req action.InvokeRequest,
 		return
 	}
 
-	attachResponse, err := client.ContainerExecAttach(ctx, execCreateResponse.ID, container.ExecAttachOptions{Tty: tty})
+	result, err := runContainerExec(ctx, client, execCreateResponse.ID, tty)
 	if err != nil {
-		resp.Diagnostics.AddError("Docker exec attach failed", err.Error())
-		return
-	}
-	defer attachResponse.Close() // nolint:errcheck
-
-	var stdout, stderr bytes.Buffer
-	if tty {
-		_, err = io.Copy(&stdout, attachResponse.Reader)
-	} else {
-		_, err = stdcopy.StdCopy(&stdout, &stderr, attachResponse.Reader)
-	}
-	if err != nil {
-		resp.Diagnostics.AddError("Docker exec output error", err.Error())
-		return
-	}
-
-	inspect, err := client.ContainerExecInspect(ctx, execCreateResponse.ID)
-	if err != nil {
-		resp.Diagnostics.AddError("Docker exec inspect failed", err.Error())
+		resp.Diagnostics.AddError("Docker exec failed", err.Error())
 		return
 	}
 
 	if resp.SendProgress != nil {
-		if out := strings.TrimSpace(stdout.String()); out != "" {
+		if out := strings.TrimSpace(result.stdout.String()); out != "" {
 			resp.SendProgress(action.InvokeProgressEvent{Message: out})
 		}
-		if errOut := strings.TrimSpace(stderr.String()); errOut != "" {
+		if errOut := strings.TrimSpace(result.stderr.String()); errOut != "" {
 			resp.SendProgress(action.InvokeProgressEvent{Message: errOut})
 		}
-		resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("exit_code=%d", inspect.ExitCode)})
+		resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("exit_code=%d", result.exitCode)})
 	}
 
-	if inspect.ExitCode != 0 {
-		details := strings.TrimSpace(stderr.String())
+	if result.exitCode != 0 {
+		details := strings.TrimSpace(result.stderr.String())
 		if details == "" {
-			details = strings.TrimSpace(stdout.String())
+			details = strings.TrimSpace(result.stdout.String())
 		}
 
 		resp.Diagnostics.AddError(
-			fmt.Sprintf("Docker exec returned non-zero exit code: %d", inspect.ExitCode),
+			fmt.Sprintf("Docker exec returned non-zero exit code: %d", result.exitCode),
 			details,
 		)
 		return
 	}
 }
 
+// containerExecResult is the output and exit code of a command run in a container.
+type containerExecResult struct {
+	stdout   bytes.Buffer
+	stderr   bytes.Buffer
+	exitCode int
+}
+
+// runContainerExec attaches to the created exec, starting it, and waits for the command to
+// exit. The attachment is closed when ctx is done, but the command keeps running in the
+// container, as it can not be killed by the Docker API.
+func runContainerExec(ctx context.Context, client *client.Client, execID string, tty bool) (*containerExecResult, error) {
+	attachResponse, err := client.ContainerExecAttach(ctx, execID, container.ExecAttachOptions{Tty: tty})
+	if err != nil {
+		return nil, fmt.Errorf("unable to attach to exec: %w", err)
+	}
+	defer attachResponse.Close() // nolint:errcheck
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			attachResponse.Close()
+		case <-done:
+		}
+	}()
+
+	result := &containerExecResult{}
+	if tty {
+		_, err = io.Copy(&result.stdout, attachResponse.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(&result.stdout, &result.stderr, attachResponse.Reader)
+	}
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	if err != nil {
+		return result, fmt.Errorf("unable to read the output of exec: %w", err)
+	}
+
+	inspect, err := client.ContainerExecInspect(ctx, execID)
+	if err != nil {
+		return result, fmt.Errorf("unable to inspect exec: %w", err)
+	}
+	result.exitCode = inspect.ExitCode
+	return result, nil
+}
+
 func getExecEnvironment(ctx context.Context, config *DockerExecActionModel) ([]string, error) {
 	result := make([]string, 0)
 
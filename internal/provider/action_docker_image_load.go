@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 	"strings"
 
 	"github.com/docker/docker/client"
@@ -29,10 +28,10 @@ func (a *DockerImageLoadAction) Metadata(ctx context.Context, req action.Metadat
 
 func (a *DockerImageLoadAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
 	resp.Schema = actionschema.Schema{
-		MarkdownDescription: "Load a Docker image from a tar archive file, similar to `docker image load`.",
+		MarkdownDescription: "Load a Docker image from a tar archive file or an OCI image layout directory, similar to `docker image load`.",
 		Attributes: map[string]actionschema.Attribute{
 			"source": actionschema.StringAttribute{
-				MarkdownDescription: "Path to a local image tar archive file.",
+				MarkdownDescription: "Path to a local image tar archive file, which may be compressed with gzip, bzip2, xz or zstd, or to an OCI image layout directory.",
 				Required:            true,
 			},
 			"quiet": actionschema.BoolAttribute{
@@ -82,12 +81,19 @@ func (a *DockerImageLoadAction) Invoke(ctx context.Context, req action.InvokeReq
 		return
 	}
 
-	sourceFile, err := os.Open(sourcePath)
+	checksum, err := calculateSourceArchiveChecksum(ctx, sourcePath)
 	if err != nil {
 		resp.Diagnostics.AddError("Invalid source", err.Error())
 		return
 	}
-	defer sourceFile.Close() // nolint:errcheck
+
+	sourceArchive, err := openSourceArchive(sourcePath)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid source", err.Error())
+		return
+	}
+	defer sourceArchive.Close() // nolint:errcheck
+	counter := &countingWriter{}
 
 	var loadOptions []client.ImageLoadOption
 	if !config.Quiet.IsNull() && !config.Quiet.IsUnknown() {
@@ -111,7 +117,7 @@ func (a *DockerImageLoadAction) Invoke(ctx context.Context, req action.InvokeReq
 		return
 	}
 
-	loadResponse, err := dockerClient.ImageLoad(ctx, sourceFile, loadOptions...)
+	loadResponse, err := dockerClient.ImageLoad(ctx, io.TeeReader(sourceArchive, counter), loadOptions...)
 	if err != nil {
 		resp.Diagnostics.AddError("Docker image load failed", err.Error())
 		return
@@ -131,5 +137,6 @@ func (a *DockerImageLoadAction) Invoke(ctx context.Context, req action.InvokeReq
 				resp.SendProgress(action.InvokeProgressEvent{Message: line})
 			}
 		}
+		resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("source=%s bytes_loaded=%d sha256=%s", sourcePath, counter.written, checksum)})
 	}
 }
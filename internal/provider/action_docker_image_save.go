@@ -1,16 +1,33 @@
 package provider
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/docker/docker/client"
 	"github.com/hashicorp/terraform-plugin-framework/action"
 	actionschema "github.com/hashicorp/terraform-plugin-framework/action/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/klauspost/compress/zstd"
+	"github.com/moby/go-archive"
+)
+
+const (
+	imageArchiveFormatDocker = "docker-archive"
+	imageArchiveFormatOCI    = "oci-layout"
+
+	imageArchiveCompressionNone = "none"
+	imageArchiveCompressionGzip = "gzip"
+	imageArchiveCompressionZstd = "zstd"
 )
 
 type DockerImageSaveAction struct {
@@ -18,9 +35,11 @@ type DockerImageSaveAction struct {
 }
 
 type DockerImageSaveActionModel struct {
-	Images   types.List   `tfsdk:"images"`
-	Output   types.String `tfsdk:"output"`
-	Platform types.String `tfsdk:"platform"`
+	Images      types.List   `tfsdk:"images"`
+	Output      types.String `tfsdk:"output"`
+	Platform    types.String `tfsdk:"platform"`
+	Format      types.String `tfsdk:"format"`
+	Compression types.String `tfsdk:"compression"`
 }
 
 func (a *DockerImageSaveAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
@@ -29,7 +48,7 @@ func (a *DockerImageSaveAction) Metadata(ctx context.Context, req action.Metadat
 
 func (a *DockerImageSaveAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
 	resp.Schema = actionschema.Schema{
-		MarkdownDescription: "Save one or more Docker images to a tar archive, similar to `docker image save`.",
+		MarkdownDescription: "Save one or more Docker images to a tar archive or an OCI image layout directory, similar to `docker image save`.",
 		Attributes: map[string]actionschema.Attribute{
 			"images": actionschema.ListAttribute{
 				MarkdownDescription: "List of image names or IDs to include in the output archive.",
@@ -37,13 +56,21 @@ func (a *DockerImageSaveAction) Schema(ctx context.Context, req action.SchemaReq
 				ElementType:         types.StringType,
 			},
 			"output": actionschema.StringAttribute{
-				MarkdownDescription: "Path to the output tar archive file.",
+				MarkdownDescription: "Path to the output tar archive file, or to the output directory if `format` is `oci-layout`. The directory is created if it does not exist and must be empty otherwise.",
 				Required:            true,
 			},
 			"platform": actionschema.StringAttribute{
 				MarkdownDescription: "Optional platform to save from a multi-platform image, for example `linux/amd64`.",
 				Optional:            true,
 			},
+			"format": actionschema.StringAttribute{
+				MarkdownDescription: "Format of the output: `docker-archive` writes the tar archive returned by the Docker daemon, `oci-layout` extracts it into an OCI image layout directory, which requires Docker Engine 25 or later. Defaults to `docker-archive`.",
+				Optional:            true,
+			},
+			"compression": actionschema.StringAttribute{
+				MarkdownDescription: "Compression of the output tar archive: `none`, `gzip` or `zstd`. Only `none` is supported for the `oci-layout` format. Defaults to `none`.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -105,6 +132,30 @@ func (a *DockerImageSaveAction) Invoke(ctx context.Context, req action.InvokeReq
 		return
 	}
 
+	format := imageArchiveFormatDocker
+	if !config.Format.IsNull() && !config.Format.IsUnknown() {
+		format = config.Format.ValueString()
+	}
+	if format != imageArchiveFormatDocker && format != imageArchiveFormatOCI {
+		resp.Diagnostics.AddError("Invalid format", fmt.Sprintf("Attribute `format` must be one of %q or %q, got %q.", imageArchiveFormatDocker, imageArchiveFormatOCI, format))
+		return
+	}
+
+	compression := imageArchiveCompressionNone
+	if !config.Compression.IsNull() && !config.Compression.IsUnknown() {
+		compression = config.Compression.ValueString()
+	}
+	switch compression {
+	case imageArchiveCompressionNone, imageArchiveCompressionGzip, imageArchiveCompressionZstd:
+	default:
+		resp.Diagnostics.AddError("Invalid compression", fmt.Sprintf("Attribute `compression` must be one of %q, %q or %q, got %q.", imageArchiveCompressionNone, imageArchiveCompressionGzip, imageArchiveCompressionZstd, compression))
+		return
+	}
+	if format == imageArchiveFormatOCI && compression != imageArchiveCompressionNone {
+		resp.Diagnostics.AddError("Invalid compression", fmt.Sprintf("Attribute `compression` must be %q if `format` is %q.", imageArchiveCompressionNone, imageArchiveFormatOCI))
+		return
+	}
+
 	var saveOptions []client.ImageSaveOption
 	if !config.Platform.IsNull() && !config.Platform.IsUnknown() {
 		parsedPlatform, err := parseOptionalPlatform(config.Platform.ValueString())
@@ -130,20 +181,120 @@ func (a *DockerImageSaveAction) Invoke(ctx context.Context, req action.InvokeReq
 	}
 	defer imageTarStream.Close() // nolint:errcheck
 
-	outputFile, err := os.Create(outputPath)
+	var writtenBytes int64
+	var checksum string
+	if format == imageArchiveFormatOCI {
+		writtenBytes, checksum, err = writeImageOCILayout(ctx, imageTarStream, outputPath)
+	} else {
+		writtenBytes, checksum, err = writeImageArchive(imageTarStream, outputPath, compression)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Output file error", err.Error())
+		resp.Diagnostics.AddError("Docker image save output error", err.Error())
 		return
 	}
+
+	if resp.SendProgress != nil {
+		resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("saved_images=%d output=%s format=%s compression=%s bytes_written=%d sha256=%s", len(imageReferences), outputPath, format, compression, writtenBytes, checksum)})
+	}
+}
+
+// writeImageArchive writes the image tar stream to the file at outputPath, compressed with the given
+// compression, and returns the number of bytes written and the sha256 checksum of the file.
+func writeImageArchive(imageTarStream io.Reader, outputPath string, compression string) (int64, string, error) {
+	outputFile, err := os.Create(outputPath)
+	if err != nil {
+		return 0, "", err
+	}
 	defer outputFile.Close() // nolint:errcheck
 
-	writtenBytes, err := io.Copy(outputFile, imageTarStream)
+	hash := sha256.New()
+	counter := &countingWriter{}
+	fileWriter := io.MultiWriter(outputFile, hash, counter)
+
+	var compressedWriter io.WriteCloser
+	switch compression {
+	case imageArchiveCompressionGzip:
+		compressedWriter = gzip.NewWriter(fileWriter)
+	case imageArchiveCompressionZstd:
+		compressedWriter, err = zstd.NewWriter(fileWriter)
+		if err != nil {
+			return 0, "", err
+		}
+	default:
+		compressedWriter = nopWriteCloser{fileWriter}
+	}
+
+	if _, err := io.Copy(compressedWriter, imageTarStream); err != nil {
+		compressedWriter.Close() // nolint:errcheck
+		return 0, "", err
+	}
+	// flushes the remaining compressed data
+	if err := compressedWriter.Close(); err != nil {
+		return 0, "", err
+	}
+	if err := outputFile.Close(); err != nil {
+		return 0, "", err
+	}
+	return counter.written, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeImageOCILayout extracts the image tar stream, which is an OCI image layout since Docker
+// Engine 25, into the directory at outputPath. It returns the size of the extracted files and
+// the checksum of the directory, which matches the checksum docker_image records for it.
+func writeImageOCILayout(ctx context.Context, imageTarStream io.Reader, outputPath string) (int64, string, error) {
+	entries, err := os.ReadDir(outputPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, "", err
+	}
+	if len(entries) > 0 {
+		return 0, "", fmt.Errorf("output directory %s is not empty", outputPath)
+	}
+	if err := os.MkdirAll(outputPath, 0o755); err != nil {
+		return 0, "", err
+	}
+
+	if err := archive.Untar(imageTarStream, outputPath, &archive.TarOptions{NoLchown: true}); err != nil {
+		return 0, "", fmt.Errorf("unable to extract the image archive to %s: %w", outputPath, err)
+	}
+	if _, err := os.Stat(filepath.Join(outputPath, "oci-layout")); err != nil {
+		return 0, "", fmt.Errorf("the Docker daemon did not return an OCI image layout, Docker Engine 25 or later is required: %w", err)
+	}
+
+	var writtenBytes int64
+	err = filepath.WalkDir(outputPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		writtenBytes += info.Size()
+		return nil
+	})
 	if err != nil {
-		resp.Diagnostics.AddError("Docker image save output error", err.Error())
-		return
+		return 0, "", err
 	}
 
-	if resp.SendProgress != nil {
-		resp.SendProgress(action.InvokeProgressEvent{Message: fmt.Sprintf("saved_images=%d output=%s bytes_written=%d", len(imageReferences), outputPath, writtenBytes)})
+	checksum, err := calculateSourceArchiveChecksum(ctx, outputPath)
+	if err != nil {
+		return 0, "", err
 	}
+	return writtenBytes, checksum, nil
 }
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	written int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	return len(p), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
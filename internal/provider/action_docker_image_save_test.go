@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteImageArchiveCompression(t *testing.T) {
+	content := bytes.Repeat([]byte("image layer "), 1024)
+
+	for _, compression := range []string{imageArchiveCompressionNone, imageArchiveCompressionGzip, imageArchiveCompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "image.tar")
+
+			writtenBytes, checksum, err := writeImageArchive(bytes.NewReader(content), outputPath, compression)
+			if err != nil {
+				t.Fatalf("writeImageArchive returned error: %s", err)
+			}
+
+			info, err := os.Stat(outputPath)
+			if err != nil {
+				t.Fatalf("failed to stat output: %s", err)
+			}
+			if writtenBytes != info.Size() {
+				t.Fatalf("expected %d bytes written, got %d", info.Size(), writtenBytes)
+			}
+			if compression != imageArchiveCompressionNone && writtenBytes >= int64(len(content)) {
+				t.Fatalf("expected the output to be compressed, got %d bytes for %d bytes of input", writtenBytes, len(content))
+			}
+
+			expectedChecksum, err := calculateSourceArchiveChecksum(context.Background(), outputPath)
+			if err != nil {
+				t.Fatalf("failed to calculate checksum: %s", err)
+			}
+			if checksum != expectedChecksum {
+				t.Fatalf("expected checksum %s, got %s", expectedChecksum, checksum)
+			}
+
+			reader, err := openSourceArchive(outputPath)
+			if err != nil {
+				t.Fatalf("openSourceArchive returned error: %s", err)
+			}
+			defer reader.Close() // nolint:errcheck
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("failed to read archive: %s", err)
+			}
+			if !bytes.Equal(data, content) {
+				t.Fatalf("expected the decompressed archive to match the input")
+			}
+		})
+	}
+}
+
+func TestWriteImageOCILayout(t *testing.T) {
+	imageTar := func(files map[string]string) io.Reader {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for name, content := range files {
+			if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+				t.Fatalf("failed to write tar header: %s", err)
+			}
+			if _, err := tw.Write([]byte(content)); err != nil {
+				t.Fatalf("failed to write tar content: %s", err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatalf("failed to close tar writer: %s", err)
+		}
+		return &buf
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "layout")
+	files := map[string]string{
+		"oci-layout":              `{"imageLayoutVersion":"1.0.0"}`,
+		"index.json":              `{"schemaVersion":2}`,
+		"blobs/sha256/0123456789": "blob",
+	}
+
+	writtenBytes, checksum, err := writeImageOCILayout(context.Background(), imageTar(files), outputPath)
+	if err != nil {
+		t.Fatalf("writeImageOCILayout returned error: %s", err)
+	}
+
+	var expectedBytes int64
+	for name, content := range files {
+		data, err := os.ReadFile(filepath.Join(outputPath, name))
+		if err != nil {
+			t.Fatalf("expected %s to be extracted: %s", name, err)
+		}
+		if string(data) != content {
+			t.Fatalf("expected %s to contain %q, got %q", name, content, string(data))
+		}
+		expectedBytes += int64(len(content))
+	}
+	if writtenBytes != expectedBytes {
+		t.Fatalf("expected %d bytes written, got %d", expectedBytes, writtenBytes)
+	}
+
+	expectedChecksum, err := calculateSourceArchiveChecksum(context.Background(), outputPath)
+	if err != nil {
+		t.Fatalf("failed to calculate checksum: %s", err)
+	}
+	if checksum != expectedChecksum {
+		t.Fatalf("expected checksum %s, got %s", expectedChecksum, checksum)
+	}
+
+	if _, _, err := writeImageOCILayout(context.Background(), imageTar(files), outputPath); err == nil {
+		t.Fatalf("expected an error for a non-empty output directory")
+	}
+
+	legacyOutputPath := filepath.Join(t.TempDir(), "legacy")
+	if _, _, err := writeImageOCILayout(context.Background(), imageTar(map[string]string{"manifest.json": "[]"}), legacyOutputPath); err == nil {
+		t.Fatalf("expected an error for an archive which is not an OCI image layout")
+	}
+}
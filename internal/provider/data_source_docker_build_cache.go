@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDockerBuildCache() *schema.Resource {
+	return &schema.Resource{
+		Description: "`docker_build_cache` lists the build cache records of the Docker daemon (`docker system df -v`) or of a buildx builder (`docker buildx du --verbose`).",
+
+		ReadContext: dataSourceDockerBuildCacheRead,
+
+		Schema: map[string]*schema.Schema{
+			"builder": {
+				Type:        schema.TypeString,
+				Description: "The name of the buildx builder. If empty, the build cache of the Docker daemon is listed.",
+				Optional:    true,
+			},
+			"filter": {
+				Type:        schema.TypeList,
+				Description: "Filter values in `key=value` format, e.g. `type=regular` or `until=24h`. Only supported together with `builder`.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"total_size": {
+				Type:        schema.TypeInt,
+				Description: "The size of all build cache records in bytes.",
+				Computed:    true,
+			},
+			"reclaimable_size": {
+				Type:        schema.TypeInt,
+				Description: "The size of the build cache records which are not in use in bytes.",
+				Computed:    true,
+			},
+			"records": {
+				Type:        schema.TypeList,
+				Description: "The build cache records, ordered by the time they were last used, most recently used first.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Description: "The ID of the build cache record.",
+							Computed:    true,
+						},
+						"node": {
+							Type:        schema.TypeString,
+							Description: "The name of the builder node the record belongs to. Empty for the Docker daemon.",
+							Computed:    true,
+						},
+						"parents": {
+							Type:        schema.TypeList,
+							Description: "The IDs of the parent records.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Description: "The type of the record, e.g. `regular`, `source.local` or `exec.cachemount`.",
+							Computed:    true,
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Description: "The description of the build step which created the record.",
+							Computed:    true,
+						},
+						"size": {
+							Type:        schema.TypeInt,
+							Description: "The size of the record in bytes.",
+							Computed:    true,
+						},
+						"shared": {
+							Type:        schema.TypeBool,
+							Description: "Whether the record is shared with other records.",
+							Computed:    true,
+						},
+						"in_use": {
+							Type:        schema.TypeBool,
+							Description: "Whether the record is in use by a running build.",
+							Computed:    true,
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Description: "The time the record was created in RFC 3339 format.",
+							Computed:    true,
+						},
+						"last_used_at": {
+							Type:        schema.TypeString,
+							Description: "The time the record was last used in RFC 3339 format. Empty if the record was never used.",
+							Computed:    true,
+						},
+						"usage_count": {
+							Type:        schema.TypeInt,
+							Description: "How often the record was used.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDockerBuildCacheRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
+	if err != nil {
+		return diag.Errorf("failed to create Docker client: %v", err)
+	}
+
+	filterArgs, err := parseSystemPruneFilterExpressions(interfaceArrayToStringArray(d.Get("filter").([]interface{})))
+	if err != nil {
+		return diag.Errorf("Invalid filter: %v", err)
+	}
+
+	builderName := d.Get("builder").(string)
+	records, err := listBuildCache(ctx, client, builderName, filterArgs)
+	if err != nil {
+		return diag.Errorf("Error listing the build cache: %v", err)
+	}
+
+	var totalSize, reclaimableSize int64
+	for _, record := range records {
+		totalSize += record.size
+		if !record.inUse {
+			reclaimableSize += record.size
+		}
+	}
+
+	if builderName == "" {
+		d.SetId("docker")
+	} else {
+		d.SetId(builderName)
+	}
+	d.Set("total_size", totalSize)
+	d.Set("reclaimable_size", reclaimableSize)
+	d.Set("records", flattenBuildCacheRecords(records))
+
+	log.Printf("[DEBUG] found %d build cache records with a total size of %d bytes", len(records), totalSize)
+	return nil
+}
+
+func flattenBuildCacheRecords(records []buildCacheRecord) []interface{} {
+	sort.SliceStable(records, func(i, j int) bool {
+		return lastUsedTime(records[i]).After(lastUsedTime(records[j]))
+	})
+
+	flattened := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		lastUsedAt := ""
+		if record.lastUsedAt != nil {
+			lastUsedAt = record.lastUsedAt.Format(time.RFC3339)
+		}
+		flattened = append(flattened, map[string]interface{}{
+			"id":           record.id,
+			"node":         record.node,
+			"parents":      record.parents,
+			"type":         record.recordType,
+			"description":  record.description,
+			"size":         int(record.size),
+			"shared":       record.shared,
+			"in_use":       record.inUse,
+			"created_at":   record.createdAt.Format(time.RFC3339),
+			"last_used_at": lastUsedAt,
+			"usage_count":  record.usageCount,
+		})
+	}
+	return flattened
+}
+
+// lastUsedTime returns the time a record was last used, falling back to its creation time.
+func lastUsedTime(record buildCacheRecord) time.Time {
+	if record.lastUsedAt != nil {
+		return *record.lastUsedAt
+	}
+	return record.createdAt
+}
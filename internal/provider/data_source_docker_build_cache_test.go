@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDockerBuildCacheDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: loadTestConfiguration(t, DATA_SOURCE, "docker_build_cache", "testAccDockerBuildCacheDataSource"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.docker_build_cache.daemon", "id", "docker"),
+					resource.TestCheckResourceAttrSet("data.docker_build_cache.daemon", "total_size"),
+					resource.TestCheckResourceAttrSet("data.docker_build_cache.daemon", "reclaimable_size"),
+				),
+			},
+			{
+				Config:      loadTestConfiguration(t, DATA_SOURCE, "docker_build_cache", "testAccDockerBuildCacheDataSourceFilterWithoutBuilder"),
+				ExpectError: regexp.MustCompile(`filters are only supported together with a buildx builder`),
+			},
+		},
+	})
+}
+
+func TestFlattenBuildCacheRecords(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lastUsed := created.Add(time.Hour)
+
+	records := []buildCacheRecord{
+		{id: "never-used", recordType: "regular", size: 10, createdAt: created},
+		{id: "recently-used", recordType: "exec.cachemount", size: 20, shared: true, inUse: true, createdAt: created, lastUsedAt: &lastUsed, usageCount: 3, parents: []string{"never-used"}},
+	}
+
+	flattened := flattenBuildCacheRecords(records)
+	if len(flattened) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(flattened))
+	}
+
+	first := flattened[0].(map[string]interface{})
+	if first["id"] != "recently-used" {
+		t.Errorf("expected the most recently used record first, got %v", first["id"])
+	}
+	if first["last_used_at"] != "2024-01-01T13:00:00Z" {
+		t.Errorf("expected last_used_at 2024-01-01T13:00:00Z, got %v", first["last_used_at"])
+	}
+	if first["size"] != 20 || first["shared"] != true || first["in_use"] != true || first["usage_count"] != 3 {
+		t.Errorf("unexpected record %v", first)
+	}
+
+	second := flattened[1].(map[string]interface{})
+	if second["last_used_at"] != "" {
+		t.Errorf("expected empty last_used_at for a record which was never used, got %v", second["last_used_at"])
+	}
+	if second["created_at"] != "2024-01-01T12:00:00Z" {
+		t.Errorf("expected created_at 2024-01-01T12:00:00Z, got %v", second["created_at"])
+	}
+}
+
+func TestFilterBuildCacheRecordsUnusedFor(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	recentlyUsed := now.Add(-time.Hour)
+	longUnused := now.Add(-48 * time.Hour)
+
+	records := []buildCacheRecord{
+		{id: "recently-created", createdAt: now.Add(-time.Minute)},
+		{id: "long-created", createdAt: now.Add(-72 * time.Hour)},
+		{id: "recently-used", createdAt: now.Add(-72 * time.Hour), lastUsedAt: &recentlyUsed},
+		{id: "long-unused", createdAt: now.Add(-72 * time.Hour), lastUsedAt: &longUnused},
+	}
+
+	if filtered := filterBuildCacheRecordsUnusedFor(records, 0, now); len(filtered) != len(records) {
+		t.Errorf("expected all records without a duration, got %v", filtered)
+	}
+
+	filtered := filterBuildCacheRecordsUnusedFor(records, 24*time.Hour, now)
+	if len(filtered) != 2 || filtered[0].id != "long-created" || filtered[1].id != "long-unused" {
+		t.Errorf("expected the records unused for more than 24h, got %v", filtered)
+	}
+}
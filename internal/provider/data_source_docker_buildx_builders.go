@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/docker/buildx/builder"
+	"github.com/docker/buildx/driver"
+	"github.com/docker/buildx/store/storeutil"
+	"github.com/docker/buildx/util/platformutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/errgroup"
+)
+
+func dataSourceDockerBuildxBuilders() *schema.Resource {
+	return &schema.Resource{
+		Description: "`docker_buildx_builders` lists the buildx builders, similar to `docker buildx ls`, and the builder buildx uses by default.",
+
+		ReadContext: dataSourceDockerBuildxBuildersRead,
+
+		Schema: map[string]*schema.Schema{
+			"resolved_builder": {
+				Type:        schema.TypeString,
+				Description: "The name of the builder buildx uses if no builder is given explicitly.",
+				Computed:    true,
+			},
+			"resolved_source": {
+				Type:        schema.TypeString,
+				Description: "Where `resolved_builder` comes from: `env:BUILDX_BUILDER` if it is set by the `BUILDX_BUILDER` environment variable, `buildx-store` if it is the current builder of the buildx store (`docker buildx use`) or `docker-context` if it is the builder of the current Docker context.",
+				Computed:    true,
+			},
+			"builders": {
+				Type:        schema.TypeList,
+				Description: "The builders of the buildx store followed by the builders of the Docker contexts.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the builder.",
+							Computed:    true,
+						},
+						"driver": {
+							Type:        schema.TypeString,
+							Description: "The driver of the builder, e.g. `docker`, `docker-container`, `kubernetes` or `remote`.",
+							Computed:    true,
+						},
+						"endpoint": {
+							Type:        schema.TypeString,
+							Description: "The endpoint of the first node of the builder.",
+							Computed:    true,
+						},
+						"platforms": {
+							Type:        schema.TypeList,
+							Description: "The platforms supported by the nodes of the builder.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Description: "The status of the builder: `running` if one of its nodes is running, otherwise the status of the first node which could be reached, e.g. `inactive` or `stopped`. Empty if the builder could not be loaded.",
+							Computed:    true,
+						},
+						"error": {
+							Type:        schema.TypeString,
+							Description: "The error returned when the builder could not be loaded.",
+							Computed:    true,
+						},
+						"current": {
+							Type:        schema.TypeBool,
+							Description: "Whether the builder is the current builder of the buildx store or the builder of the current Docker context.",
+							Computed:    true,
+						},
+						"resolved": {
+							Type:        schema.TypeBool,
+							Description: "Whether the builder is the `resolved_builder`.",
+							Computed:    true,
+						},
+						"nodes": {
+							Type:        schema.TypeList,
+							Description: "The nodes of the builder.",
+							Computed:    true,
+							Elem:        buildxBuilderNodeSchema(),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDockerBuildxBuildersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
+	if err != nil {
+		return diag.Errorf("failed to create Docker client: %v", err)
+	}
+
+	dockerCli, err := createAndInitDockerCli(client)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create and init Docker CLI: %w", err))
+	}
+
+	// resolved before the store is opened below, as it locks the store itself
+	resolvedBuilder, err := ResolveBuilderLikeBuildx(ctx, dockerCli, "")
+	if err != nil {
+		return diag.Errorf("error resolving default builder: %v", err)
+	}
+
+	txn, release, err := storeutil.GetStore(dockerCli)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer release()
+
+	current, err := storeutil.GetCurrentInstance(txn, dockerCli)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	builders, err := builder.GetBuilders(dockerCli, txn)
+	if err != nil {
+		return diag.Errorf("Error listing the buildx builders: %v", err)
+	}
+
+	// the data of the nodes is loaded from the drivers, do not wait forever for unreachable ones
+	loadCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	eg, _ := errgroup.WithContext(loadCtx)
+	for _, b := range builders {
+		eg.Go(func() error {
+			// errors are reported per builder and node
+			_, _ = b.LoadNodes(loadCtx, builder.WithData())
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	currentName := ""
+	if current != nil {
+		currentName = current.Name
+	}
+
+	d.SetId(resolvedBuilder.Name)
+	d.Set("resolved_builder", resolvedBuilder.Name)
+	d.Set("resolved_source", string(resolvedBuilder.Source))
+	d.Set("builders", flattenBuildxBuilders(builders, currentName, resolvedBuilder.Name))
+
+	log.Printf("[DEBUG] found %d buildx builders, resolved builder is %s (%s)", len(builders), resolvedBuilder.Name, resolvedBuilder.Source)
+	return nil
+}
+
+func flattenBuildxBuilders(builders []*builder.Builder, currentName, resolvedName string) []interface{} {
+	flattened := make([]interface{}, 0, len(builders))
+	for _, b := range builders {
+		nodes := b.Nodes()
+
+		endpoint := ""
+		if len(b.NodeGroup.Nodes) > 0 {
+			endpoint = b.NodeGroup.Nodes[0].Endpoint
+		}
+
+		errorMessage := ""
+		if b.Err() != nil {
+			errorMessage = b.Err().Error()
+		}
+
+		var platforms []ocispecs.Platform
+		for _, node := range nodes {
+			platforms = append(platforms, node.Node.Platforms...)
+			platforms = append(platforms, node.Platforms...)
+		}
+
+		flattened = append(flattened, map[string]interface{}{
+			"name":      b.Name,
+			"driver":    b.Driver,
+			"endpoint":  endpoint,
+			"platforms": platformutil.Format(platformutil.Dedupe(platforms)),
+			"status":    buildxBuilderStatus(b.Err(), nodes),
+			"error":     errorMessage,
+			"current":   b.Name == currentName,
+			"resolved":  b.Name == resolvedName,
+			"nodes":     flattenBuildxBuilderNodes(nodes),
+		})
+	}
+	return flattened
+}
+
+// buildxBuilderStatus returns running if one of the nodes is running, otherwise the status of
+// the first node which could be reached.
+func buildxBuilderStatus(err error, nodes []builder.Node) string {
+	if err != nil {
+		return ""
+	}
+
+	status := ""
+	for _, node := range nodes {
+		if node.Err != nil || node.DriverInfo == nil {
+			continue
+		}
+		if node.DriverInfo.Status == driver.Running {
+			return driver.Running.String()
+		}
+		if status == "" {
+			status = node.DriverInfo.Status.String()
+		}
+	}
+	return status
+}
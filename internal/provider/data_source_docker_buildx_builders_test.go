@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/buildx/builder"
+	"github.com/docker/buildx/driver"
+	"github.com/docker/buildx/store"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDockerBuildxBuildersDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: loadTestConfiguration(t, DATA_SOURCE, "docker_buildx_builders", "testAccDockerBuildxBuildersDataSource"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.docker_buildx_builders.all", "resolved_builder"),
+					resource.TestCheckResourceAttrSet("data.docker_buildx_builders.all", "resolved_source"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.docker_buildx_builders.all", "builders.*", map[string]string{
+						"name":    "foo-builders",
+						"driver":  "docker-container",
+						"status":  "running",
+						"nodes.#": "1",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestBuildxBuilderStatus(t *testing.T) {
+	node := func(status driver.Status) builder.Node {
+		return builder.Node{Node: store.Node{Name: status.String()}, DriverInfo: &driver.Info{Status: status}}
+	}
+	unreachable := builder.Node{Node: store.Node{Name: "unreachable"}, Err: errors.New("connection refused")}
+
+	cases := []struct {
+		name     string
+		err      error
+		nodes    []builder.Node
+		expected string
+	}{
+		{name: "no nodes", expected: ""},
+		{name: "builder error", err: errors.New("no valid drivers found"), nodes: []builder.Node{node(driver.Running)}, expected: ""},
+		{name: "any running", nodes: []builder.Node{node(driver.Stopped), node(driver.Running)}, expected: "running"},
+		{name: "first reachable", nodes: []builder.Node{unreachable, node(driver.Inactive), node(driver.Stopped)}, expected: "inactive"},
+		{name: "unreachable", nodes: []builder.Node{unreachable}, expected: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if status := buildxBuilderStatus(c.err, c.nodes); status != c.expected {
+				t.Errorf("expected status %q, got %q", c.expected, status)
+			}
+		})
+	}
+}
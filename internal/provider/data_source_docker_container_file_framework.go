@@ -0,0 +1,243 @@
+package provider
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// containerFileMaxLinks is the maximum number of symbolic links which are followed, the same
+// as the limit of Linux.
+const containerFileMaxLinks = 40
+
+var (
+	_ datasource.DataSource              = &dockerContainerFileDataSource{}
+	_ datasource.DataSourceWithConfigure = &dockerContainerFileDataSource{}
+)
+
+type dockerContainerFileDataSource struct {
+	providerConfig *ProviderConfig
+}
+
+type dockerContainerFileDataSourceModel struct {
+	ID                     types.String `tfsdk:"id"`
+	Container              types.String `tfsdk:"container"`
+	Path                   types.String `tfsdk:"path"`
+	Sensitive              types.Bool   `tfsdk:"sensitive"`
+	Content                types.String `tfsdk:"content"`
+	ContentBase64          types.String `tfsdk:"content_base64"`
+	SensitiveContent       types.String `tfsdk:"sensitive_content"`
+	SensitiveContentBase64 types.String `tfsdk:"sensitive_content_base64"`
+	SHA256                 types.String `tfsdk:"sha256"`
+	Size                   types.Int64  `tfsdk:"size"`
+	Mode                   types.String `tfsdk:"mode"`
+	ModTime                types.String `tfsdk:"mod_time"`
+}
+
+func NewDockerContainerFileDataSource() datasource.DataSource {
+	return &dockerContainerFileDataSource{}
+}
+
+func (d *dockerContainerFileDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_container_file"
+}
+
+func (d *dockerContainerFileDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads a file out of a container, for example a password or token the container generated when it started. Symbolic links are followed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of this data source, in the form of `<container>:<path>`.",
+				Computed:            true,
+			},
+			"container": schema.StringAttribute{
+				MarkdownDescription: "The name or ID of the container to read the file from. The container does not have to be running.",
+				Required:            true,
+			},
+			"path": schema.StringAttribute{
+				MarkdownDescription: "The absolute path of the file in the container.",
+				Required:            true,
+			},
+			"sensitive": schema.BoolAttribute{
+				MarkdownDescription: "If `true`, the content of the file is only set in `sensitive_content` and `sensitive_content_base64`, which are not shown in the plan output, instead of `content` and `content_base64`. Defaults to `false`.",
+				Optional:            true,
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "The content of the file as UTF-8 encoded text. Use `content_base64` for binary files. Not set if `sensitive` is `true`.",
+				Computed:            true,
+			},
+			"content_base64": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded content of the file. Not set if `sensitive` is `true`.",
+				Computed:            true,
+			},
+			"sensitive_content": schema.StringAttribute{
+				MarkdownDescription: "The content of the file as UTF-8 encoded text if `sensitive` is `true`.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"sensitive_content_base64": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded content of the file if `sensitive` is `true`.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"sha256": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded sha256 checksum of the content of the file. Not set if `sensitive` is `true`.",
+				Computed:            true,
+			},
+			"size": schema.Int64Attribute{
+				MarkdownDescription: "The size of the file in bytes.",
+				Computed:            true,
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "The permission mode of the file in octal notation, for example `0644`.",
+				Computed:            true,
+			},
+			"mod_time": schema.StringAttribute{
+				MarkdownDescription: "The time the file was last modified, in RFC 3339 format.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *dockerContainerFileDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerConfig = providerConfig
+}
+
+func (d *dockerContainerFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.providerConfig == nil {
+		resp.Diagnostics.AddError("Provider not configured", "The provider configuration is unavailable for docker_container_file data source.")
+		return
+	}
+
+	var state dockerContainerFileDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	containerName := state.Container.ValueString()
+	filePath := state.Path.ValueString()
+	if !path.IsAbs(filePath) {
+		resp.Diagnostics.AddError("Invalid path", fmt.Sprintf("The path %q in the container has to be absolute.", filePath))
+		return
+	}
+
+	client, err := d.providerConfig.MakeClient(ctx, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker client error", fmt.Sprintf("Unable to create Docker client: %s", err))
+		return
+	}
+
+	filePath, err = resolveContainerFileLinks(filePath, func(linkPath string) (container.PathStat, error) {
+		return client.ContainerStatPath(ctx, containerName, linkPath)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Docker container file read failed", fmt.Sprintf("Unable to stat %s in container %s: %s", state.Path.ValueString(), containerName, err))
+		return
+	}
+
+	reader, _, err := client.CopyFromContainer(ctx, containerName, filePath)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker container file read failed", fmt.Sprintf("Unable to copy %s from container %s: %s", filePath, containerName, err))
+		return
+	}
+	defer reader.Close() //nolint:errcheck
+
+	content, header, err := readContainerFileArchive(reader)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker container file read failed", fmt.Sprintf("Unable to read %s from container %s: %s", filePath, containerName, err))
+		return
+	}
+
+	flattenDockerContainerFile(&state, content, header)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// resolveContainerFileLinks follows symbolic links like `docker cp --follow-link` does, and
+// fails if more than containerFileMaxLinks links have to be followed.
+func resolveContainerFileLinks(filePath string, stat func(string) (container.PathStat, error)) (string, error) {
+	for range containerFileMaxLinks {
+		pathStat, err := stat(filePath)
+		if err != nil {
+			return "", err
+		}
+		if pathStat.Mode&os.ModeSymlink == 0 {
+			return filePath, nil
+		}
+
+		linkTarget := pathStat.LinkTarget
+		if !path.IsAbs(linkTarget) {
+			linkTarget = path.Join(path.Dir(filePath), linkTarget)
+		}
+		filePath = linkTarget
+	}
+	return "", fmt.Errorf("too many levels of symbolic links")
+}
+
+// readContainerFileArchive returns the content and header of the single regular file in the
+// archive returned by CopyFromContainer.
+func readContainerFileArchive(reader io.Reader) ([]byte, *tar.Header, error) {
+	tr := tar.NewReader(reader)
+	header, err := tr.Next()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the archive: %w", err)
+	}
+	if header.Typeflag != tar.TypeReg {
+		return nil, nil, fmt.Errorf("%s is not a regular file", header.Name)
+	}
+
+	content, err := io.ReadAll(tr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read the archive: %w", err)
+	}
+	return content, header, nil
+}
+
+func flattenDockerContainerFile(state *dockerContainerFileDataSourceModel, content []byte, header *tar.Header) {
+	checksum := sha256.Sum256(content)
+
+	state.ID = types.StringValue(fmt.Sprintf("%s:%s", state.Container.ValueString(), state.Path.ValueString()))
+	state.Size = types.Int64Value(int64(len(content)))
+	state.Mode = types.StringValue(fmt.Sprintf("%04o", header.FileInfo().Mode().Perm()))
+	state.ModTime = types.StringValue(header.ModTime.UTC().Format(time.RFC3339))
+
+	text := types.StringValue(string(content))
+	encoded := types.StringValue(base64.StdEncoding.EncodeToString(content))
+	if state.Sensitive.ValueBool() {
+		state.Content, state.ContentBase64 = types.StringNull(), types.StringNull()
+		state.SensitiveContent, state.SensitiveContentBase64 = text, encoded
+		// the checksum of a short secret would reveal it
+		state.SHA256 = types.StringNull()
+	} else {
+		state.Content, state.ContentBase64 = text, encoded
+		state.SensitiveContent, state.SensitiveContentBase64 = types.StringNull(), types.StringNull()
+		state.SHA256 = types.StringValue(hex.EncodeToString(checksum[:]))
+	}
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDockerContainerFileDataSource_Metadata(t *testing.T) {
+	dataSource := NewDockerContainerFileDataSource()
+	resp := datasource.MetadataResponse{}
+
+	dataSource.Metadata(context.Background(), datasource.MetadataRequest{
+		ProviderTypeName: "docker",
+	}, &resp)
+
+	if resp.TypeName != "docker_container_file" {
+		t.Fatalf("expected type name docker_container_file, got %s", resp.TypeName)
+	}
+}
+
+func TestResolveContainerFileLinks(t *testing.T) {
+	links := map[string]string{
+		"/etc/app/token":     "../../run/secrets/token",
+		"/run/secrets/token": "/data/token",
+		"/loop/a":            "b",
+		"/loop/b":            "a",
+	}
+	stat := func(filePath string) (container.PathStat, error) {
+		if linkTarget, ok := links[filePath]; ok {
+			return container.PathStat{Name: filePath, Mode: os.ModeSymlink | 0777, LinkTarget: linkTarget}, nil
+		}
+		if filePath == "/data/token" {
+			return container.PathStat{Name: filePath, Mode: 0600}, nil
+		}
+		return container.PathStat{}, fmt.Errorf("no such file %s", filePath)
+	}
+
+	resolved, err := resolveContainerFileLinks("/etc/app/token", stat)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resolved != "/data/token" {
+		t.Errorf("expected the links to be resolved to /data/token, got %s", resolved)
+	}
+
+	if _, err := resolveContainerFileLinks("/loop/a", stat); err == nil {
+		t.Errorf("expected an error for a loop of symbolic links")
+	}
+	if _, err := resolveContainerFileLinks("/missing", stat); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+}
+
+func TestReadContainerFileArchive(t *testing.T) {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	if err := tw.WriteHeader(&tar.Header{Name: "admin-password", Typeflag: tar.TypeReg, Mode: 0o600, Size: 6}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte("s3cr3t")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content, header, err := readContainerFileArchive(&archive)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(content) != "s3cr3t" || header.Name != "admin-password" {
+		t.Fatalf("unexpected file %s with content %q", header.Name, content)
+	}
+
+	var dirArchive bytes.Buffer
+	tw = tar.NewWriter(&dirArchive)
+	if err := tw.WriteHeader(&tar.Header{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := readContainerFileArchive(&dirArchive); err == nil {
+		t.Fatalf("expected an error for a directory")
+	}
+}
+
+func TestFlattenDockerContainerFile(t *testing.T) {
+	header := &tar.Header{
+		Name:     "token",
+		Typeflag: tar.TypeReg,
+		Mode:     0o640,
+		ModTime:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	state := dockerContainerFileDataSourceModel{
+		Container: types.StringValue("tf-test"),
+		Path:      types.StringValue("/run/token"),
+		Sensitive: types.BoolNull(),
+	}
+	flattenDockerContainerFile(&state, []byte("hello"), header)
+
+	if state.ID.ValueString() != "tf-test:/run/token" {
+		t.Fatalf("unexpected id %s", state.ID.ValueString())
+	}
+	if state.Content.ValueString() != "hello" || state.ContentBase64.ValueString() != "aGVsbG8=" {
+		t.Fatalf("unexpected content %q and content_base64 %q", state.Content.ValueString(), state.ContentBase64.ValueString())
+	}
+	if !state.SensitiveContent.IsNull() || !state.SensitiveContentBase64.IsNull() {
+		t.Fatalf("expected no sensitive content")
+	}
+	if expected := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"; state.SHA256.ValueString() != expected {
+		t.Fatalf("expected sha256 %s, got %s", expected, state.SHA256.ValueString())
+	}
+	if state.Size.ValueInt64() != 5 || state.Mode.ValueString() != "0640" || state.ModTime.ValueString() != "2024-05-01T12:00:00Z" {
+		t.Fatalf("unexpected size %d, mode %s or mod_time %s", state.Size.ValueInt64(), state.Mode.ValueString(), state.ModTime.ValueString())
+	}
+
+	state.Sensitive = types.BoolValue(true)
+	flattenDockerContainerFile(&state, []byte("hello"), header)
+	if !state.Content.IsNull() || !state.ContentBase64.IsNull() {
+		t.Fatalf("expected the content to only be set in the sensitive attributes")
+	}
+	if state.SensitiveContent.ValueString() != "hello" || state.SensitiveContentBase64.ValueString() != "aGVsbG8=" {
+		t.Fatalf("unexpected sensitive content %q", state.SensitiveContent.ValueString())
+	}
+	if !state.SHA256.IsNull() {
+		t.Fatalf("expected no sha256 of sensitive content, got %s", state.SHA256.ValueString())
+	}
+}
@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/docker/buildx/build"
+	dockeropts "github.com/docker/cli/opts"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/moby/buildkit/frontend/subrequests/lint"
+	"github.com/moby/buildkit/frontend/subrequests/targets"
+	solverpb "github.com/moby/buildkit/solver/pb"
+)
+
+func dataSourceDockerDockerfileCheck() *schema.Resource {
+	return &schema.Resource{
+		Description: "`docker_dockerfile_check` runs the build checks of a Dockerfile (`docker buildx build --call=check`) with a buildx builder. Every check violation is reported as a diagnostic with the file and line, so broken Dockerfiles fail at plan time. Please see https://docs.docker.com/build/checks/ for the available checks.",
+
+		ReadContext: dataSourceDockerDockerfileCheckRead,
+
+		Schema: map[string]*schema.Schema{
+			"context": {
+				Type:        schema.TypeString,
+				Description: "The path to the build context. The path is resolved on the machine running Terraform.",
+				Required:    true,
+			},
+			"dockerfile": {
+				Type:        schema.TypeString,
+				Description: "Name of the Dockerfile. If it is not an absolute path, it is resolved relative to `context`. Defaults to `Dockerfile`.",
+				Optional:    true,
+				Default:     "Dockerfile",
+			},
+			"target": {
+				Type:        schema.TypeString,
+				Description: "The target build stage to check. Defaults to the last stage.",
+				Optional:    true,
+			},
+			"build_args": {
+				Type:        schema.TypeMap,
+				Description: "Pairs for build-time variables in the form of `ENDPOINT : \"https://example.com\"`",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"builder": {
+				Type:        schema.TypeString,
+				Description: "The name of the buildx builder to use. If BUILDX_BUILDER environment variable is set, it will be used. If left empty, the provider resolves the builder the same way as the buildx CLI.",
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("BUILDX_BUILDER", ""),
+			},
+			"fail_on_warnings": {
+				Type:        schema.TypeBool,
+				Description: "If `true`, check violations are reported as errors instead of warnings. Defaults to `false`.",
+				Optional:    true,
+				Default:     false,
+			},
+			"warnings": {
+				Type:        schema.TypeList,
+				Description: "The check violations of the Dockerfile.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rule_name": {
+							Type:        schema.TypeString,
+							Description: "The name of the violated check, e.g. `JSONArgsRecommended`.",
+							Computed:    true,
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Description: "The description of the check.",
+							Computed:    true,
+						},
+						"detail": {
+							Type:        schema.TypeString,
+							Description: "The details of the violation.",
+							Computed:    true,
+						},
+						"url": {
+							Type:        schema.TypeString,
+							Description: "The URL of the documentation of the check.",
+							Computed:    true,
+						},
+						"file": {
+							Type:        schema.TypeString,
+							Description: "The file which contains the violation.",
+							Computed:    true,
+						},
+						"line": {
+							Type:        schema.TypeInt,
+							Description: "The line which contains the violation.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"targets": {
+				Type:        schema.TypeList,
+				Description: "The build stages of the Dockerfile.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the stage.",
+							Computed:    true,
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Description: "The description of the stage taken from the comment above it.",
+							Computed:    true,
+						},
+						"base": {
+							Type:        schema.TypeString,
+							Description: "The base image of the stage.",
+							Computed:    true,
+						},
+						"platform": {
+							Type:        schema.TypeString,
+							Description: "The platform of the stage, if set.",
+							Computed:    true,
+						},
+						"default": {
+							Type:        schema.TypeBool,
+							Description: "Whether the stage is built by default.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDockerDockerfileCheckRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
+	if err != nil {
+		return diag.Errorf("failed to create Docker client: %v", err)
+	}
+
+	dockerCli, err := createAndInitDockerCli(client)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create and init Docker CLI: %w", err))
+	}
+
+	contextDir, dockerfilePath, _, err := resolveDockerfilePath(d.Get("context").(string), d.Get("dockerfile").(string))
+	if err != nil {
+		return diag.Errorf("error resolving dockerfile path: %v", err)
+	}
+
+	options := buildOptions{
+		contextPath:    contextDir,
+		dockerfileName: dockerfilePath,
+		target:         d.Get("target").(string),
+		builder:        d.Get("builder").(string),
+		ulimits:        dockeropts.NewUlimitOpt(nil),
+	}
+	for key, value := range d.Get("build_args").(map[string]interface{}) {
+		options.buildArgs = append(options.buildArgs, fmt.Sprintf("%s=%s", key, value.(string)))
+	}
+	if options.builder == "" {
+		resolvedBuilder, err := ResolveBuilderLikeBuildx(ctx, dockerCli, "")
+		if err != nil {
+			return diag.Errorf("error resolving default builder: %v", err)
+		}
+		options.builder = resolvedBuilder.Name
+	}
+
+	checkResponse, inputs, err := runBuildCall(ctx, dockerCli, options, "check")
+	if err != nil {
+		return diag.Errorf("Error running the build checks of %s: %v", dockerfilePath, err)
+	}
+	lintResults := lint.LintResults{}
+	if result, ok := checkResponse["result.json"]; ok {
+		if err := json.Unmarshal([]byte(result), &lintResults); err != nil {
+			return diag.Errorf("Error decoding the build check results: %v", err)
+		}
+	}
+
+	targetsResponse, _, err := runBuildCall(ctx, dockerCli, options, "targets")
+	if err != nil {
+		return diag.Errorf("Error listing the targets of %s: %v", dockerfilePath, err)
+	}
+	targetList := targets.List{}
+	if result, ok := targetsResponse["result.json"]; ok {
+		if err := json.Unmarshal([]byte(result), &targetList); err != nil {
+			return diag.Errorf("Error decoding the targets: %v", err)
+		}
+	}
+
+	d.SetId(dockerfilePath)
+	d.Set("warnings", flattenLintWarnings(lintResults, inputs))
+	d.Set("targets", flattenDockerfileTargets(targetList))
+
+	log.Printf("[DEBUG] Dockerfile %s has %d check violations", dockerfilePath, len(lintResults.Warnings))
+	return lintResultsToDiagnostics(lintResults, inputs, d.Get("fail_on_warnings").(bool))
+}
+
+// lintSourceLocation returns the file and line of a lint location. Locations in the
+// Dockerfile are reported with the name of the Dockerfile as given to the build, other
+// sources keep their own name.
+func lintSourceLocation(sources []*solverpb.SourceInfo, location *solverpb.Location, inputs *build.Inputs) (string, int) {
+	if location == nil {
+		return "", 0
+	}
+
+	var file string
+	if idx := int(location.SourceIndex); idx >= 0 && idx < len(sources) && sources[idx] != nil {
+		file = sources[idx].Filename
+	}
+	if inputs != nil && inputs.DockerfileMappingSrc != "" && file == inputs.DockerfileMappingDst {
+		file = inputs.DockerfileMappingSrc
+	}
+
+	var line int
+	if len(location.Ranges) > 0 && location.Ranges[0].Start != nil {
+		line = int(location.Ranges[0].Start.Line)
+	}
+	return file, line
+}
+
+func flattenLintWarnings(lintResults lint.LintResults, inputs *build.Inputs) []interface{} {
+	warnings := make([]interface{}, 0, len(lintResults.Warnings))
+	for _, warning := range lintResults.Warnings {
+		file, line := lintSourceLocation(lintResults.Sources, warning.Location, inputs)
+		warnings = append(warnings, map[string]interface{}{
+			"rule_name":   warning.RuleName,
+			"description": warning.Description,
+			"detail":      warning.Detail,
+			"url":         warning.URL,
+			"file":        file,
+			"line":        line,
+		})
+	}
+	return warnings
+}
+
+func flattenDockerfileTargets(targetList targets.List) []interface{} {
+	flattened := make([]interface{}, 0, len(targetList.Targets))
+	for _, target := range targetList.Targets {
+		flattened = append(flattened, map[string]interface{}{
+			"name":        target.Name,
+			"description": target.Description,
+			"base":        target.Base,
+			"platform":    target.Platform,
+			"default":     target.Default,
+		})
+	}
+	return flattened
+}
+
+// lintResultsToDiagnostics turns every check violation into a diagnostic. A build error,
+// e.g. a syntax error in the Dockerfile, is always reported as an error.
+func lintResultsToDiagnostics(lintResults lint.LintResults, inputs *build.Inputs, failOnWarnings bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	severity := diag.Warning
+	if failOnWarnings {
+		severity = diag.Error
+	}
+	for _, warning := range lintResults.Warnings {
+		file, line := lintSourceLocation(lintResults.Sources, warning.Location, inputs)
+		detail := fmt.Sprintf("%s:%d: %s", file, line, warning.Description)
+		if warning.URL != "" {
+			detail = fmt.Sprintf("%s\n\nMore info: %s", detail, warning.URL)
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity:      severity,
+			Summary:       fmt.Sprintf("%s: %s", warning.RuleName, warning.Detail),
+			Detail:        detail,
+			AttributePath: cty.GetAttrPath("dockerfile"),
+		})
+	}
+
+	if lintResults.Error != nil {
+		file, line := lintSourceLocation(lintResults.Sources, &lintResults.Error.Location, inputs)
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       lintResults.Error.Message,
+			Detail:        fmt.Sprintf("%s:%d", file, line),
+			AttributePath: cty.GetAttrPath("dockerfile"),
+		})
+	}
+
+	return diags
+}
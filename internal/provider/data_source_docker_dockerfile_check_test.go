@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/buildx/build"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/moby/buildkit/frontend/subrequests/lint"
+	solverpb "github.com/moby/buildkit/solver/pb"
+)
+
+func TestAccDockerDockerfileCheckDataSource_basic(t *testing.T) {
+	contextDir := t.TempDir()
+	dockerfile := "FROM alpine:3.20 as build\nRUN echo hello\n\nFROM build AS final\nCMD echo hello\n"
+	if err := os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+		t.Fatalf("Failed to create Dockerfile: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, DATA_SOURCE, "docker_dockerfile_check", "testAccDockerDockerfileCheckDataSource"), contextDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.docker_dockerfile_check.foo", "targets.#", "2"),
+					resource.TestCheckResourceAttr("data.docker_dockerfile_check.foo", "targets.1.name", "final"),
+					resource.TestCheckResourceAttr("data.docker_dockerfile_check.foo", "warnings.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.docker_dockerfile_check.foo", "warnings.*", map[string]string{
+						"rule_name": "FromAsCasing",
+						"line":      "1",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("data.docker_dockerfile_check.foo", "warnings.*", map[string]string{
+						"rule_name": "JSONArgsRecommended",
+						"line":      "5",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestLintResultsToDiagnostics(t *testing.T) {
+	lintResults := lint.LintResults{
+		Sources: []*solverpb.SourceInfo{{Filename: "Dockerfile"}},
+		Warnings: []lint.Warning{
+			{
+				RuleName:    "JSONArgsRecommended",
+				Description: "JSON arguments recommended for ENTRYPOINT/CMD to prevent unintended behavior related to OS signals",
+				URL:         "https://docs.docker.com/go/dockerfile/rule/json-args-recommended/",
+				Detail:      "JSON arguments recommended for CMD to prevent unintended behavior related to OS signals",
+				Location: &solverpb.Location{
+					SourceIndex: 0,
+					Ranges:      []*solverpb.Range{{Start: &solverpb.Position{Line: 5}, End: &solverpb.Position{Line: 5}}},
+				},
+			},
+		},
+	}
+
+	diags := lintResultsToDiagnostics(lintResults, nil, false)
+	if len(diags) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %v", diags)
+	}
+	if diags[0].Severity != diag.Warning {
+		t.Errorf("Expected a warning, got severity %v", diags[0].Severity)
+	}
+	expectedDetail := "Dockerfile:5: JSON arguments recommended for ENTRYPOINT/CMD to prevent unintended behavior related to OS signals\n\nMore info: https://docs.docker.com/go/dockerfile/rule/json-args-recommended/"
+	if diags[0].Detail != expectedDetail {
+		t.Errorf("Expected detail %q, got %q", expectedDetail, diags[0].Detail)
+	}
+
+	inputs := &build.Inputs{DockerfileMappingSrc: "build/Dockerfile", DockerfileMappingDst: "Dockerfile"}
+	diags = lintResultsToDiagnostics(lintResults, inputs, true)
+	if !diags.HasError() {
+		t.Errorf("Expected warnings to be reported as errors with fail_on_warnings")
+	}
+	if file, line := lintSourceLocation(lintResults.Sources, lintResults.Warnings[0].Location, inputs); file != "build/Dockerfile" || line != 5 {
+		t.Errorf("Expected build/Dockerfile:5, got %s:%d", file, line)
+	}
+
+	lintResults.Sources = append(lintResults.Sources, &solverpb.SourceInfo{Filename: "frontend.dockerfile"})
+	location := &solverpb.Location{SourceIndex: 1, Ranges: []*solverpb.Range{{Start: &solverpb.Position{Line: 3}}}}
+	if file, line := lintSourceLocation(lintResults.Sources, location, inputs); file != "frontend.dockerfile" || line != 3 {
+		t.Errorf("Expected frontend.dockerfile:3, got %s:%d", file, line)
+	}
+
+	lintResults.Warnings = nil
+	lintResults.Error = &lint.BuildError{
+		Message:  "dockerfile parse error on line 2: unknown instruction: RUNN",
+		Location: solverpb.Location{Ranges: []*solverpb.Range{{Start: &solverpb.Position{Line: 2}}}},
+	}
+	diags = lintResultsToDiagnostics(lintResults, nil, false)
+	if len(diags) != 1 || !diags.HasError() {
+		t.Fatalf("Expected the build error to be reported as an error, got %v", diags)
+	}
+	if diags[0].Detail != "Dockerfile:2" {
+		t.Errorf("Expected detail Dockerfile:2, got %q", diags[0].Detail)
+	}
+}
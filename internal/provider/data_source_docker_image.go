@@ -3,9 +3,11 @@ package provider
 import (
 	"context"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/docker/docker/api/types/image"
+	dockerclient "github.com/docker/docker/client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -27,6 +29,133 @@ func dataSourceDockerImage() *schema.Resource {
 				Description: "The image sha256 digest in the form of `repo[:tag]@sha256:<hash>`. It may be empty in the edge case where the local image was pulled from a repo, tagged locally, and then referred to in the data source by that local name/tag.",
 				Computed:    true,
 			},
+			"platform": {
+				Type:        schema.TypeString,
+				Description: "The platform to inspect if the image has several variants, e.g. `linux/arm64`. Requires Docker Engine 28.1 or later. Defaults to the platform of the Docker host.",
+				Optional:    true,
+			},
+			"architecture": {
+				Type:        schema.TypeString,
+				Description: "The CPU architecture of the image, e.g. `amd64`.",
+				Computed:    true,
+			},
+			"os": {
+				Type:        schema.TypeString,
+				Description: "The operating system of the image, e.g. `linux`.",
+				Computed:    true,
+			},
+			"variant": {
+				Type:        schema.TypeString,
+				Description: "The variant of the CPU architecture of the image, e.g. `v8` for `arm64`.",
+				Computed:    true,
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Description: "The size of the image in bytes.",
+				Computed:    true,
+			},
+			"created": {
+				Type:        schema.TypeString,
+				Description: "The date and time the image was created in RFC 3339 format.",
+				Computed:    true,
+			},
+			"layers": {
+				Type:        schema.TypeList,
+				Description: "The digests of the layers of the root filesystem of the image.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"env": {
+				Type:        schema.TypeList,
+				Description: "The environment variables of the image in the form of `KEY=value`.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"entrypoint": {
+				Type:        schema.TypeList,
+				Description: "The entrypoint of the image.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"command": {
+				Type:        schema.TypeList,
+				Description: "The default command of the image.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"working_dir": {
+				Type:        schema.TypeString,
+				Description: "The working directory of the image.",
+				Computed:    true,
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Description: "The user the image runs as.",
+				Computed:    true,
+			},
+			"stop_signal": {
+				Type:        schema.TypeString,
+				Description: "The signal to stop a container of the image.",
+				Computed:    true,
+			},
+			"exposed_ports": {
+				Type:        schema.TypeList,
+				Description: "The ports exposed by the image in the form of `port/protocol`, e.g. `80/tcp`.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"volumes": {
+				Type:        schema.TypeList,
+				Description: "The volumes declared by the image.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"labels": {
+				Type:        schema.TypeMap,
+				Description: "The labels of the image.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"healthcheck": {
+				Type:        schema.TypeList,
+				Description: "The healthcheck of the image.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"test": {
+							Type:        schema.TypeList,
+							Description: "The command to run to check the health, e.g. `[\"CMD-SHELL\", \"curl -f localhost/health\"]`.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"interval": {
+							Type:        schema.TypeString,
+							Description: "Time between running the check.",
+							Computed:    true,
+						},
+						"timeout": {
+							Type:        schema.TypeString,
+							Description: "Maximum time to allow one check to run.",
+							Computed:    true,
+						},
+						"start_period": {
+							Type:        schema.TypeString,
+							Description: "Start period for the container to initialize before counting retries towards unstable.",
+							Computed:    true,
+						},
+						"start_interval": {
+							Type:        schema.TypeString,
+							Description: "Interval before the healthcheck starts.",
+							Computed:    true,
+						},
+						"retries": {
+							Type:        schema.TypeInt,
+							Description: "Consecutive failures needed to report unhealthy.",
+							Computed:    true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -53,13 +182,94 @@ func dataSourceDockerImageRead(ctx context.Context, d *schema.ResourceData, meta
 
 	repoDigest := determineRepoDigest(imageName, foundImage)
 
+	var inspectOptions []dockerclient.ImageInspectOption
+	platform, err := parseOptionalPlatform(d.Get("platform").(string))
+	if err != nil {
+		return diag.Errorf("invalid platform %q: %s", d.Get("platform").(string), err)
+	}
+	if platform != nil {
+		inspectOptions = append(inspectOptions, dockerclient.ImageInspectWithPlatform(platform))
+	}
+
+	imageInspect, err := client.ImageInspect(ctx, foundImage.ID, inspectOptions...)
+	if err != nil {
+		return diag.Errorf("dataSourceDockerImageRead: error inspecting image %q: %s", imageName, err)
+	}
+
 	d.SetId(foundImage.ID)
 	d.Set("name", imageName)
 	d.Set("repo_digest", repoDigest)
+	for key, value := range flattenImageInspect(imageInspect) {
+		d.Set(key, value)
+	}
 
 	return nil
 }
 
+// flattenImageInspect returns the computed attributes of the data source for the inspected image.
+func flattenImageInspect(imageInspect image.InspectResponse) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"architecture":  imageInspect.Architecture,
+		"os":            imageInspect.Os,
+		"variant":       imageInspect.Variant,
+		"size":          int(imageInspect.Size),
+		"created":       imageInspect.Created,
+		"layers":        imageInspect.RootFS.Layers,
+		"env":           []string{},
+		"entrypoint":    []string{},
+		"command":       []string{},
+		"working_dir":   "",
+		"user":          "",
+		"stop_signal":   "",
+		"exposed_ports": []string{},
+		"volumes":       []string{},
+		"labels":        map[string]string{},
+		"healthcheck":   []interface{}{},
+	}
+
+	config := imageInspect.Config
+	if config == nil {
+		return attributes
+	}
+
+	exposedPorts := make([]string, 0, len(config.ExposedPorts))
+	for port := range config.ExposedPorts {
+		exposedPorts = append(exposedPorts, port)
+	}
+	sort.Strings(exposedPorts)
+
+	volumes := make([]string, 0, len(config.Volumes))
+	for volume := range config.Volumes {
+		volumes = append(volumes, volume)
+	}
+	sort.Strings(volumes)
+
+	attributes["env"] = config.Env
+	attributes["entrypoint"] = config.Entrypoint
+	attributes["command"] = config.Cmd
+	attributes["working_dir"] = config.WorkingDir
+	attributes["user"] = config.User
+	attributes["stop_signal"] = config.StopSignal
+	attributes["exposed_ports"] = exposedPorts
+	attributes["volumes"] = volumes
+	if config.Labels != nil {
+		attributes["labels"] = config.Labels
+	}
+	if config.Healthcheck != nil {
+		attributes["healthcheck"] = []interface{}{
+			map[string]interface{}{
+				"test":           config.Healthcheck.Test,
+				"interval":       config.Healthcheck.Interval.String(),
+				"timeout":        config.Healthcheck.Timeout.String(),
+				"start_period":   config.Healthcheck.StartPeriod.String(),
+				"start_interval": config.Healthcheck.StartInterval.String(),
+				"retries":        config.Healthcheck.Retries,
+			},
+		}
+	}
+	return attributes
+}
+
 // determineRepoDigest determines the repo digest for a local image name.
 // It will always return a digest and if none was found it returns an empty string.
 // See https://github.com/kreuzwerker/terraform-provider-docker/pull/212#discussion_r646025706 for details
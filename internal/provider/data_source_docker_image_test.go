@@ -4,14 +4,18 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 var imageRepoDigestRegexp = regexp.MustCompile(`^.*@sha256:[A-Fa-f0-9]+$`)
@@ -33,6 +37,14 @@ func TestAccDockerImageDataSource_withSpecificTag(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("data.docker_image.foo", "name", imageName),
 					resource.TestCheckResourceAttr("data.docker_image.foo", "repo_digest", "busybox@sha256:e8e5cca392e3cf056fcdb3093e7ac2bf83fcf28b3bcf5818fe8ae71cf360c231"),
+					resource.TestCheckResourceAttr("data.docker_image.foo", "os", "linux"),
+					resource.TestCheckResourceAttrSet("data.docker_image.foo", "architecture"),
+					resource.TestCheckResourceAttrSet("data.docker_image.foo", "created"),
+					resource.TestCheckResourceAttrSet("data.docker_image.foo", "size"),
+					resource.TestCheckResourceAttr("data.docker_image.foo", "layers.#", "1"),
+					resource.TestCheckResourceAttr("data.docker_image.foo", "command.#", "1"),
+					resource.TestCheckResourceAttr("data.docker_image.foo", "command.0", "sh"),
+					resource.TestCheckResourceAttr("data.docker_image.foo", "healthcheck.#", "0"),
 				),
 			},
 		},
@@ -158,6 +170,78 @@ func TestAccDockerImageDataSource_withNonExistentImage(t *testing.T) {
 }
 
 // Helpers
+func TestFlattenImageInspect(t *testing.T) {
+	imageInspect := image.InspectResponse{
+		Architecture: "arm64",
+		Os:           "linux",
+		Variant:      "v8",
+		Size:         4096,
+		Created:      "2024-01-02T03:04:05Z",
+		RootFS: image.RootFS{
+			Type:   "layers",
+			Layers: []string{"sha256:aaaa", "sha256:bbbb"},
+		},
+		Config: &dockerspec.DockerOCIImageConfig{
+			ImageConfig: ocispec.ImageConfig{
+				User:         "nobody",
+				ExposedPorts: map[string]struct{}{"8080/tcp": {}, "443/tcp": {}},
+				Env:          []string{"PATH=/bin"},
+				Entrypoint:   []string{"/entrypoint.sh"},
+				Cmd:          []string{"serve"},
+				Volumes:      map[string]struct{}{"/var/lib/data": {}, "/cache": {}},
+				WorkingDir:   "/app",
+				Labels:       map[string]string{"maintainer": "ops"},
+				StopSignal:   "SIGTERM",
+			},
+			DockerOCIImageConfigExt: dockerspec.DockerOCIImageConfigExt{
+				Healthcheck: &dockerspec.HealthcheckConfig{
+					Test:     []string{"CMD-SHELL", "true"},
+					Interval: 30 * time.Second,
+					Retries:  3,
+				},
+			},
+		},
+	}
+
+	attributes := flattenImageInspect(imageInspect)
+
+	expected := map[string]interface{}{
+		"architecture":  "arm64",
+		"os":            "linux",
+		"variant":       "v8",
+		"size":          4096,
+		"created":       "2024-01-02T03:04:05Z",
+		"layers":        []string{"sha256:aaaa", "sha256:bbbb"},
+		"env":           []string{"PATH=/bin"},
+		"entrypoint":    []string{"/entrypoint.sh"},
+		"command":       []string{"serve"},
+		"working_dir":   "/app",
+		"user":          "nobody",
+		"stop_signal":   "SIGTERM",
+		"exposed_ports": []string{"443/tcp", "8080/tcp"},
+		"volumes":       []string{"/cache", "/var/lib/data"},
+		"labels":        map[string]string{"maintainer": "ops"},
+		"healthcheck": []interface{}{
+			map[string]interface{}{
+				"test":           []string{"CMD-SHELL", "true"},
+				"interval":       "30s",
+				"timeout":        "0s",
+				"start_period":   "0s",
+				"start_interval": "0s",
+				"retries":        3,
+			},
+		},
+	}
+	if !reflect.DeepEqual(attributes, expected) {
+		t.Fatalf("expected %#v, got %#v", expected, attributes)
+	}
+
+	attributes = flattenImageInspect(image.InspectResponse{Os: "linux"})
+	if healthcheck := attributes["healthcheck"].([]interface{}); len(healthcheck) != 0 {
+		t.Fatalf("expected no healthcheck for an image without config, got %#v", healthcheck)
+	}
+}
+
 func pullImageForTest(t *testing.T, imageName string) {
 	cmd := exec.Command("docker", "pull", imageName)
 	if err := cmd.Run(); err != nil {
@@ -0,0 +1,315 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var (
+	_ datasource.DataSource              = &dockerImagesDataSource{}
+	_ datasource.DataSourceWithConfigure = &dockerImagesDataSource{}
+)
+
+type dockerImagesDataSource struct {
+	providerConfig *ProviderConfig
+}
+
+type dockerImagesDataSourceModel struct {
+	ID        types.String                 `tfsdk:"id"`
+	Reference types.List                   `tfsdk:"reference"`
+	Label     types.List                   `tfsdk:"label"`
+	Dangling  types.Bool                   `tfsdk:"dangling"`
+	Before    types.String                 `tfsdk:"before"`
+	Since     types.String                 `tfsdk:"since"`
+	Platform  types.String                 `tfsdk:"platform"`
+	Images    []dockerImageDataSourceModel `tfsdk:"images"`
+}
+
+type dockerImageDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	RepoTags    types.List   `tfsdk:"repo_tags"`
+	RepoDigests types.List   `tfsdk:"repo_digests"`
+	Size        types.Int64  `tfsdk:"size"`
+	Created     types.Int64  `tfsdk:"created"`
+	Labels      types.Map    `tfsdk:"labels"`
+}
+
+func NewDockerImagesDataSource() datasource.DataSource {
+	return &dockerImagesDataSource{}
+}
+
+func (d *dockerImagesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_images"
+}
+
+func (d *dockerImagesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists Docker images from the local Docker daemon, similar to `docker image ls`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The ID of this data source.",
+				Computed:            true,
+			},
+			"reference": schema.ListAttribute{
+				MarkdownDescription: "Only list images whose reference matches one of the given patterns, for example `busybox:*` or `registry.example.com/app`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"label": schema.ListAttribute{
+				MarkdownDescription: "Only list images with all of the given labels, in the form of `key` or `key=value`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"dangling": schema.BoolAttribute{
+				MarkdownDescription: "Only list untagged images if `true`, or only tagged images if `false`.",
+				Optional:            true,
+			},
+			"before": schema.StringAttribute{
+				MarkdownDescription: "Only list images created before the given image name or ID.",
+				Optional:            true,
+			},
+			"since": schema.StringAttribute{
+				MarkdownDescription: "Only list images created after the given image name or ID.",
+				Optional:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Only list images which are available for the given platform, for example `linux/arm64`. The `size` of the images is the size of this platform then. Requires the containerd image store.",
+				Optional:            true,
+			},
+			"images": schema.ListNestedAttribute{
+				MarkdownDescription: "List of Docker images.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The Docker image ID.",
+							Computed:            true,
+						},
+						"repo_tags": schema.ListAttribute{
+							MarkdownDescription: "The names and tags referencing the image.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"repo_digests": schema.ListAttribute{
+							MarkdownDescription: "The repository digests of the image in the form of `repo@sha256:<hash>`.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"size": schema.Int64Attribute{
+							MarkdownDescription: "The size of the image in bytes.",
+							Computed:            true,
+						},
+						"created": schema.Int64Attribute{
+							MarkdownDescription: "The Unix timestamp when the image was created.",
+							Computed:            true,
+						},
+						"labels": schema.MapAttribute{
+							MarkdownDescription: "Labels applied to the image.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *dockerImagesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerConfig, ok := req.ProviderData.(*ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ProviderConfig, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.providerConfig = providerConfig
+}
+
+func (d *dockerImagesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.providerConfig == nil {
+		resp.Diagnostics.AddError("Provider not configured", "The provider configuration is unavailable for docker_images data source.")
+		return
+	}
+
+	var state dockerImagesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	listFilters, diags := dockerImagesListFilters(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	platform, err := parseOptionalPlatform(state.Platform.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid platform", err.Error())
+		return
+	}
+
+	client, err := d.providerConfig.MakeClient(ctx, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Docker client error", fmt.Sprintf("Unable to create Docker client: %s", err))
+		return
+	}
+
+	apiImages, err := client.ImageList(ctx, image.ListOptions{
+		Filters:   listFilters,
+		Manifests: platform != nil,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Docker image list failed", err.Error())
+		return
+	}
+
+	if platform != nil {
+		apiImages, err = filterDockerImagesByPlatform(apiImages, *platform)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid platform", err.Error())
+			return
+		}
+	}
+
+	sort.Slice(apiImages, func(i, j int) bool {
+		return apiImages[i].ID < apiImages[j].ID
+	})
+
+	images, diags := flattenDockerImages(ctx, apiImages)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = types.StringValue("docker_images")
+	state.Images = images
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// dockerImagesListFilters returns the filters of the image list for the configured attributes.
+func dockerImagesListFilters(ctx context.Context, config dockerImagesDataSourceModel) (filters.Args, diag.Diagnostics) {
+	listFilters := filters.NewArgs()
+	var diags diag.Diagnostics
+
+	for _, listFilter := range []struct {
+		name  string
+		value types.List
+	}{
+		{name: "reference", value: config.Reference},
+		{name: "label", value: config.Label},
+	} {
+		if listFilter.value.IsNull() || listFilter.value.IsUnknown() {
+			continue
+		}
+
+		var values []string
+		diags.Append(listFilter.value.ElementsAs(ctx, &values, false)...)
+		if diags.HasError() {
+			return listFilters, diags
+		}
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				listFilters.Add(listFilter.name, value)
+			}
+		}
+	}
+
+	if !config.Dangling.IsNull() && !config.Dangling.IsUnknown() {
+		listFilters.Add("dangling", strconv.FormatBool(config.Dangling.ValueBool()))
+	}
+	if before := strings.TrimSpace(config.Before.ValueString()); before != "" {
+		listFilters.Add("before", before)
+	}
+	if since := strings.TrimSpace(config.Since.ValueString()); since != "" {
+		listFilters.Add("since", since)
+	}
+
+	return listFilters, diags
+}
+
+// filterDockerImagesByPlatform returns the images with an available image manifest for the
+// platform, with the size of that manifest. The manifests are only listed by the daemon if
+// the containerd image store is used.
+func filterDockerImagesByPlatform(apiImages []image.Summary, platform specs.Platform) ([]image.Summary, error) {
+	matcher := platforms.NewMatcher(platform)
+
+	result := make([]image.Summary, 0, len(apiImages))
+	for _, apiImage := range apiImages {
+		if apiImage.Manifests == nil {
+			return nil, fmt.Errorf("the Docker daemon did not list the platforms of image %s, filtering by platform requires the containerd image store", apiImage.ID)
+		}
+
+		for _, manifest := range apiImage.Manifests {
+			if manifest.Kind != image.ManifestKindImage || !manifest.Available || manifest.ImageData == nil {
+				continue
+			}
+			if !matcher.Match(manifest.ImageData.Platform) {
+				continue
+			}
+
+			apiImage.Size = manifest.Size.Total
+			result = append(result, apiImage)
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func flattenDockerImages(ctx context.Context, apiImages []image.Summary) ([]dockerImageDataSourceModel, diag.Diagnostics) {
+	result := make([]dockerImageDataSourceModel, 0, len(apiImages))
+	diags := make(diag.Diagnostics, 0)
+
+	for _, apiImage := range apiImages {
+		repoTags, repoTagsDiags := types.ListValueFrom(ctx, types.StringType, apiImage.RepoTags)
+		diags.Append(repoTagsDiags...)
+		if repoTagsDiags.HasError() {
+			return nil, diags
+		}
+
+		repoDigests, repoDigestsDiags := types.ListValueFrom(ctx, types.StringType, apiImage.RepoDigests)
+		diags.Append(repoDigestsDiags...)
+		if repoDigestsDiags.HasError() {
+			return nil, diags
+		}
+
+		labels, labelsDiags := types.MapValueFrom(ctx, types.StringType, apiImage.Labels)
+		diags.Append(labelsDiags...)
+		if labelsDiags.HasError() {
+			return nil, diags
+		}
+
+		result = append(result, dockerImageDataSourceModel{
+			ID:          types.StringValue(apiImage.ID),
+			RepoTags:    repoTags,
+			RepoDigests: repoDigests,
+			Size:        types.Int64Value(apiImage.Size),
+			Created:     types.Int64Value(apiImage.Created),
+			Labels:      labels,
+		})
+	}
+
+	return result, diags
+}
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestDockerImagesDataSource_Metadata(t *testing.T) {
+	dataSource := NewDockerImagesDataSource()
+	resp := datasource.MetadataResponse{}
+
+	dataSource.Metadata(context.Background(), datasource.MetadataRequest{
+		ProviderTypeName: "docker",
+	}, &resp)
+
+	if resp.TypeName != "docker_images" {
+		t.Fatalf("expected type name docker_images, got %s", resp.TypeName)
+	}
+}
+
+func TestDockerImagesListFilters(t *testing.T) {
+	ctx := context.Background()
+
+	listFilters, diags := dockerImagesListFilters(ctx, dockerImagesDataSourceModel{
+		Reference: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("busybox:*"), types.StringValue(" ")}),
+		Label:     types.ListValueMust(types.StringType, []attr.Value{types.StringValue("team=ops"), types.StringValue("terraform")}),
+		Dangling:  types.BoolValue(false),
+		Before:    types.StringNull(),
+		Since:     types.StringValue("busybox:1.35.0"),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	expected := map[string][]string{
+		"reference": {"busybox:*"},
+		"label":     {"team=ops", "terraform"},
+		"dangling":  {"false"},
+		"since":     {"busybox:1.35.0"},
+	}
+	for key, values := range expected {
+		// the values of a filter are not ordered
+		got := listFilters.Get(key)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, values) {
+			t.Fatalf("expected filter %s to be %v, got %v", key, values, got)
+		}
+	}
+	if listFilters.Contains("before") {
+		t.Fatalf("expected no before filter, got %v", listFilters.Get("before"))
+	}
+
+	listFilters, diags = dockerImagesListFilters(ctx, dockerImagesDataSourceModel{
+		Reference: types.ListNull(types.StringType),
+		Label:     types.ListNull(types.StringType),
+		Dangling:  types.BoolNull(),
+	})
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if listFilters.Len() != 0 {
+		t.Fatalf("expected no filters, got %v", listFilters.Keys())
+	}
+}
+
+func TestFilterDockerImagesByPlatform(t *testing.T) {
+	manifest := func(platform specs.Platform, available bool, size int64) image.ManifestSummary {
+		summary := image.ManifestSummary{
+			Kind:      image.ManifestKindImage,
+			Available: available,
+			ImageData: &image.ImageProperties{Platform: platform},
+		}
+		summary.Size.Total = size
+		return summary
+	}
+	amd64 := specs.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := specs.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+
+	apiImages := []image.Summary{
+		{ID: "sha256:multi", Size: 300, Manifests: []image.ManifestSummary{manifest(amd64, true, 100), manifest(arm64, true, 200)}},
+		{ID: "sha256:amd64", Size: 100, Manifests: []image.ManifestSummary{manifest(amd64, true, 100)}},
+		{ID: "sha256:missing", Size: 100, Manifests: []image.ManifestSummary{manifest(amd64, true, 100), manifest(arm64, false, 200)}},
+	}
+
+	filtered, err := filterDockerImagesByPlatform(apiImages, specs.Platform{OS: "linux", Architecture: "arm64"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(filtered) != 1 || filtered[0].ID != "sha256:multi" || filtered[0].Size != 200 {
+		t.Fatalf("expected only sha256:multi with the size of the arm64 manifest, got %#v", filtered)
+	}
+
+	if _, err := filterDockerImagesByPlatform([]image.Summary{{ID: "sha256:classic"}}, amd64); err == nil {
+		t.Fatalf("expected an error for images without manifests")
+	}
+}
+
+func TestFlattenDockerImages(t *testing.T) {
+	ctx := context.Background()
+
+	images, diags := flattenDockerImages(ctx, []image.Summary{
+		{
+			ID:          "sha256:abc123",
+			RepoTags:    []string{"busybox:1.35.0"},
+			RepoDigests: []string{"busybox@sha256:def456"},
+			Size:        4096,
+			Created:     42,
+			Labels: map[string]string{
+				"terraform": "true",
+			},
+		},
+	})
+
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if len(images) != 1 {
+		t.Fatalf("expected 1 image, got %d", len(images))
+	}
+
+	if images[0].ID.ValueString() != "sha256:abc123" {
+		t.Fatalf("expected ID sha256:abc123, got %s", images[0].ID.ValueString())
+	}
+	if images[0].Size.ValueInt64() != 4096 || images[0].Created.ValueInt64() != 42 {
+		t.Fatalf("unexpected size %d or created %d", images[0].Size.ValueInt64(), images[0].Created.ValueInt64())
+	}
+
+	var repoTags []string
+	diags = images[0].RepoTags.ElementsAs(ctx, &repoTags, false)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics decoding repo tags: %v", diags)
+	}
+	if len(repoTags) != 1 || repoTags[0] != "busybox:1.35.0" {
+		t.Fatalf("unexpected repo tags: %#v", repoTags)
+	}
+
+	var repoDigests []string
+	diags = images[0].RepoDigests.ElementsAs(ctx, &repoDigests, false)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics decoding repo digests: %v", diags)
+	}
+	if len(repoDigests) != 1 || repoDigests[0] != "busybox@sha256:def456" {
+		t.Fatalf("unexpected repo digests: %#v", repoDigests)
+	}
+
+	var labels map[string]string
+	diags = images[0].Labels.ElementsAs(ctx, &labels, false)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics decoding labels: %v", diags)
+	}
+	if labels["terraform"] != "true" {
+		t.Fatalf("expected terraform label to be true, got %#v", labels)
+	}
+}
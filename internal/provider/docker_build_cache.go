@@ -0,0 +1,322 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/buildx/builder"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/client"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// buildCacheRecord is a build cache record of the daemon or of a node of a buildx builder.
+type buildCacheRecord struct {
+	id          string
+	node        string
+	parents     []string
+	recordType  string
+	description string
+	size        int64
+	shared      bool
+	inUse       bool
+	createdAt   time.Time
+	lastUsedAt  *time.Time
+	usageCount  int
+}
+
+// buildCachePruneOptions are the options of a build cache prune, the same as the ones
+// of `docker buildx prune`.
+type buildCachePruneOptions struct {
+	all         bool
+	keepStorage int64
+	filters     filters.Args
+}
+
+// buildCachePruneResult is the outcome of a build cache prune.
+type buildCachePruneResult struct {
+	recordsDeleted int
+	spaceReclaimed int64
+}
+
+// listBuildCache returns the build cache records of the daemon. If builderName is set,
+// the records of all nodes of the buildx builder are returned instead.
+func listBuildCache(ctx context.Context, dockerClient *client.Client, builderName string, filterArgs filters.Args) ([]buildCacheRecord, error) {
+	if builderName == "" {
+		if filterArgs.Len() > 0 {
+			return nil, fmt.Errorf("filters are only supported together with a buildx builder")
+		}
+
+		diskUsage, err := dockerClient.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.BuildCacheObject}})
+		if err != nil {
+			return nil, err
+		}
+
+		records := make([]buildCacheRecord, 0, len(diskUsage.BuildCache))
+		for _, cacheRecord := range diskUsage.BuildCache {
+			records = append(records, buildCacheRecord{
+				id:          cacheRecord.ID,
+				parents:     cacheRecord.Parents,
+				recordType:  cacheRecord.Type,
+				description: cacheRecord.Description,
+				size:        cacheRecord.Size,
+				shared:      cacheRecord.Shared,
+				inUse:       cacheRecord.InUse,
+				createdAt:   cacheRecord.CreatedAt,
+				lastUsedAt:  cacheRecord.LastUsedAt,
+				usageCount:  cacheRecord.UsageCount,
+			})
+		}
+		return records, nil
+	}
+
+	pruneInfo, err := toBuildkitPruneInfo(filterArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := loadBuilderNodes(ctx, dockerClient, builderName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu      sync.Mutex
+		records []buildCacheRecord
+	)
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, node := range nodes {
+		if node.Driver == nil {
+			continue
+		}
+		eg.Go(func() error {
+			c, err := node.Driver.Client(ctx)
+			if err != nil {
+				return err
+			}
+			usage, err := c.DiskUsage(ctx, bkclient.WithFilter(pruneInfo.Filter))
+			if err != nil {
+				return fmt.Errorf("unable to read the build cache of node %s: %w", node.Name, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, usageInfo := range usage {
+				records = append(records, buildCacheRecordFromUsageInfo(node.Name, usageInfo))
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	return filterBuildCacheRecordsUnusedFor(records, pruneInfo.KeepDuration, time.Now()), nil
+}
+
+// filterBuildCacheRecordsUnusedFor returns the records which have not been used for longer
+// than the given duration, the same records the "until" filter selects when pruning.
+// Records which have never been used are selected by the time they were created.
+func filterBuildCacheRecordsUnusedFor(records []buildCacheRecord, unusedFor time.Duration, now time.Time) []buildCacheRecord {
+	if unusedFor == 0 {
+		return records
+	}
+
+	filtered := make([]buildCacheRecord, 0, len(records))
+	for _, record := range records {
+		lastUsedAt := record.createdAt
+		if record.lastUsedAt != nil {
+			lastUsedAt = *record.lastUsedAt
+		}
+		if now.Sub(lastUsedAt) > unusedFor {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// pruneBuildCache removes build cache of the daemon. If builderName is set, the build
+// cache of all nodes of the buildx builder is removed instead.
+func pruneBuildCache(ctx context.Context, dockerClient *client.Client, builderName string, options buildCachePruneOptions) (buildCachePruneResult, error) {
+	if builderName == "" {
+		pruneOptions := build.CachePruneOptions{
+			All:     options.all,
+			Filters: options.filters,
+		}
+		if versions.LessThan(dockerClient.ClientVersion(), "1.48") {
+			pruneOptions.KeepStorage = options.keepStorage
+		} else {
+			pruneOptions.ReservedSpace = options.keepStorage
+		}
+
+		report, err := dockerClient.BuildCachePrune(ctx, pruneOptions)
+		if err != nil {
+			return buildCachePruneResult{}, err
+		}
+		return buildCachePruneResult{
+			recordsDeleted: len(report.CachesDeleted),
+			spaceReclaimed: int64(report.SpaceReclaimed),
+		}, nil
+	}
+
+	pruneInfo, err := toBuildkitPruneInfo(options.filters)
+	if err != nil {
+		return buildCachePruneResult{}, err
+	}
+
+	nodes, err := loadBuilderNodes(ctx, dockerClient, builderName)
+	if err != nil {
+		return buildCachePruneResult{}, err
+	}
+
+	ch := make(chan bkclient.UsageInfo)
+	result := buildCachePruneResult{}
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for usageInfo := range ch {
+			result.recordsDeleted++
+			result.spaceReclaimed += usageInfo.Size
+		}
+	}()
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	for _, node := range nodes {
+		if node.Driver == nil {
+			continue
+		}
+		eg.Go(func() error {
+			c, err := node.Driver.Client(egCtx)
+			if err != nil {
+				return err
+			}
+			pruneOptions := []bkclient.PruneOption{
+				bkclient.WithKeepOpt(pruneInfo.KeepDuration, options.keepStorage, 0, 0),
+				bkclient.WithFilter(pruneInfo.Filter),
+			}
+			if options.all {
+				pruneOptions = append(pruneOptions, bkclient.PruneAll)
+			}
+			if err := c.Prune(egCtx, ch, pruneOptions...); err != nil {
+				return fmt.Errorf("unable to prune the build cache of node %s: %w", node.Name, err)
+			}
+			return nil
+		})
+	}
+	err = eg.Wait()
+	close(ch)
+	<-collected
+
+	return result, err
+}
+
+// loadBuilderNodes loads the nodes of the buildx builder with the given name and fails if
+// one of them is not reachable.
+func loadBuilderNodes(ctx context.Context, dockerClient *client.Client, builderName string) ([]builder.Node, error) {
+	dockerCli, err := createAndInitDockerCli(dockerClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create and init Docker CLI: %w", err)
+	}
+
+	b, err := builder.New(dockerCli, builder.WithName(builderName))
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := b.LoadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		if node.Err != nil {
+			return nil, fmt.Errorf("node %s of builder %s is not available: %w", node.Name, b.Name, node.Err)
+		}
+	}
+	return nodes, nil
+}
+
+func buildCacheRecordFromUsageInfo(nodeName string, usageInfo *bkclient.UsageInfo) buildCacheRecord {
+	return buildCacheRecord{
+		id:          usageInfo.ID,
+		node:        nodeName,
+		parents:     usageInfo.Parents,
+		recordType:  string(usageInfo.RecordType),
+		description: usageInfo.Description,
+		size:        usageInfo.Size,
+		shared:      usageInfo.Shared,
+		inUse:       usageInfo.InUse,
+		createdAt:   usageInfo.CreatedAt,
+		lastUsedAt:  usageInfo.LastUsedAt,
+		usageCount:  usageInfo.UsageCount,
+	}
+}
+
+// toBuildkitPruneInfo converts the filters of the docker CLI to the buildkit format.
+// taken from https://github.com/docker/buildx/blob/master/commands/prune.go
+func toBuildkitPruneInfo(f filters.Args) (*bkclient.PruneInfo, error) {
+	var until time.Duration
+	untilValues := f.Get("until")          // canonical
+	unusedForValues := f.Get("unused-for") // deprecated synonym for "until" filter
+
+	if len(untilValues) > 0 && len(unusedForValues) > 0 {
+		return nil, errors.Errorf("conflicting filters %q and %q", "until", "unused-for")
+	}
+	untilKey := "until"
+	if len(unusedForValues) > 0 {
+		untilKey = "unused-for"
+	}
+	untilValues = append(untilValues, unusedForValues...)
+
+	switch len(untilValues) {
+	case 0:
+		// nothing to do
+	case 1:
+		var err error
+		until, err = time.ParseDuration(untilValues[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "%q filter expects a duration (e.g., '24h')", untilKey)
+		}
+	default:
+		return nil, errors.Errorf("filters expect only one value")
+	}
+
+	// the keys are sorted to get a stable filter
+	filterKeys := f.Keys()
+	sort.Strings(filterKeys)
+
+	bkFilters := make([]string, 0, f.Len())
+	for _, filterKey := range filterKeys {
+		if filterKey == untilKey {
+			continue
+		}
+
+		values := f.Get(filterKey)
+		switch len(values) {
+		case 0:
+			bkFilters = append(bkFilters, filterKey)
+		case 1:
+			if filterKey == "id" {
+				bkFilters = append(bkFilters, filterKey+"~="+values[0])
+			} else if strings.HasSuffix(filterKey, "!") || strings.HasSuffix(filterKey, "~") {
+				bkFilters = append(bkFilters, filterKey+"="+values[0])
+			} else {
+				bkFilters = append(bkFilters, filterKey+"=="+values[0])
+			}
+		default:
+			return nil, errors.Errorf("filters expect only one value")
+		}
+	}
+	return &bkclient.PruneInfo{
+		KeepDuration: until,
+		Filter:       []string{strings.Join(bkFilters, ",")},
+	}, nil
+}
@@ -0,0 +1,346 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command/image/build"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/moby/patternmatcher"
+	"golang.org/x/sync/errgroup"
+)
+
+// contextFileDigest is a cached digest of a single file of a build context.
+// The digest is reused as long as the size and modification time of the file
+// did not change.
+type contextFileDigest struct {
+	size    int64
+	modTime time.Time
+	digest  string
+}
+
+// contextFileDigestCache caches file digests for the lifetime of the provider
+// process, so that repeated diffs of large build contexts only re-read files
+// which were modified in between.
+var contextFileDigestCache sync.Map
+
+type contextHashEntry struct {
+	relPath string
+	absPath string
+	info    fs.FileInfo
+	digest  string
+}
+
+// readBuildContextExcludes returns the exclude patterns of the .dockerignore file
+// in contextDir, with the Dockerfile and .dockerignore removed from them. These are
+// the same rules which are applied when the build context is sent to the daemon.
+func readBuildContextExcludes(contextDir string, dockerfile string) ([]string, error) {
+	excludes, err := build.ReadDockerignore(contextDir)
+	if err != nil {
+		return nil, err
+	}
+	return build.TrimBuildFilesFromExcludes(excludes, dockerfile, false), nil
+}
+
+// calculateBuildContextHash calculates a sha256 hash over all files of the build context
+// which are not excluded via .dockerignore, the Dockerfile and the build args.
+func calculateBuildContextHash(ctx context.Context, rawBuild map[string]interface{}) (string, error) {
+	contextPath, _ := rawBuild["context"].(string)
+	dockerfile, _ := rawBuild["dockerfile"].(string)
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+
+	contextDir, dockerfilePath, _, err := resolveDockerfilePath(contextPath, dockerfile)
+	if err != nil {
+		return "", err
+	}
+
+	excludes, err := readBuildContextExcludes(contextDir, dockerfile)
+	if err != nil {
+		return "", fmt.Errorf("unable to read .dockerignore: %w", err)
+	}
+
+	entries, err := walkBuildContext(contextDir, excludes)
+	if err != nil {
+		return "", fmt.Errorf("unable to walk build context %s: %w", contextDir, err)
+	}
+
+	if err := digestBuildContextEntries(ctx, entries); err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, entry := range entries {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\n", filepath.ToSlash(entry.relPath), entry.info.Mode(), entry.digest) // nolint:errcheck
+	}
+
+	dockerfileDigest, err := digestFile(dockerfilePath)
+	if err != nil {
+		return "", fmt.Errorf("unable to hash dockerfile %s: %w", dockerfilePath, err)
+	}
+	fmt.Fprintf(h, "dockerfile\x00%s\n", dockerfileDigest) // nolint:errcheck
+
+	if buildArgs, ok := rawBuild["build_args"].(map[string]interface{}); ok {
+		keys := make([]string, 0, len(buildArgs))
+		for key := range buildArgs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(h, "build_arg\x00%s=%v\n", key, buildArgs[key]) // nolint:errcheck
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// walkBuildContext collects all entries of the build context in contextDir, skipping
+// the ones matched by the excludes in the same way the build context tar is created.
+func walkBuildContext(contextDir string, excludes []string) ([]*contextHashEntry, error) {
+	pm, err := patternmatcher.New(excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		entries         []*contextHashEntry
+		parentMatchInfo []patternmatcher.MatchInfo
+		parentDirs      []string
+	)
+
+	err = filepath.WalkDir(contextDir, func(filePath string, f fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relFilePath, err := filepath.Rel(contextDir, filePath)
+		if err != nil || relFilePath == "." {
+			return err
+		}
+
+		for len(parentDirs) != 0 {
+			lastParentDir := parentDirs[len(parentDirs)-1]
+			if strings.HasPrefix(relFilePath, lastParentDir+string(os.PathSeparator)) {
+				break
+			}
+			parentDirs = parentDirs[:len(parentDirs)-1]
+			parentMatchInfo = parentMatchInfo[:len(parentMatchInfo)-1]
+		}
+
+		var parentInfo patternmatcher.MatchInfo
+		if len(parentMatchInfo) != 0 {
+			parentInfo = parentMatchInfo[len(parentMatchInfo)-1]
+		}
+		skip, matchInfo, err := pm.MatchesUsingParentResults(relFilePath, parentInfo)
+		if err != nil {
+			return err
+		}
+
+		if f.IsDir() {
+			parentDirs = append(parentDirs, relFilePath)
+			parentMatchInfo = append(parentMatchInfo, matchInfo)
+		}
+
+		if skip {
+			if !f.IsDir() {
+				return nil
+			}
+			// a directory can only be skipped completely if no exclusion
+			// pattern (e.g. !dir/file) re-includes something below it
+			if !pm.Exclusions() {
+				return filepath.SkipDir
+			}
+			dirSlash := relFilePath + string(filepath.Separator)
+			for _, pat := range pm.Patterns() {
+				if pat.Exclusion() && strings.HasPrefix(pat.String()+string(filepath.Separator), dirSlash) {
+					return nil
+				}
+			}
+			return filepath.SkipDir
+		}
+
+		info, err := f.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, &contextHashEntry{
+			relPath: relFilePath,
+			absPath: filePath,
+			info:    info,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// digestBuildContextEntries calculates the digests of the given entries in parallel.
+func digestBuildContextEntries(ctx context.Context, entries []*contextHashEntry) error {
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(runtime.NumCPU())
+
+	for _, entry := range entries {
+		switch {
+		case entry.info.IsDir():
+			continue
+		case entry.info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(entry.absPath)
+			if err != nil {
+				return err
+			}
+			entry.digest = target
+			continue
+		case !entry.info.Mode().IsRegular():
+			continue
+		}
+
+		eg.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			digest, err := digestFileCached(entry.absPath, entry.info)
+			if err != nil {
+				return fmt.Errorf("unable to hash %s: %w", entry.absPath, err)
+			}
+			entry.digest = digest
+			return nil
+		})
+	}
+
+	return eg.Wait()
+}
+
+// sumContextHashEntries returns a sha256 hash over the paths, modes and digests of the
+// digested entries.
+func sumContextHashEntries(entries []*contextHashEntry) string {
+	h := sha256.New()
+	for _, entry := range entries {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\n", filepath.ToSlash(entry.relPath), entry.info.Mode(), entry.digest) // nolint:errcheck
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// digestFileCached returns the sha256 digest of the file at path. The digest is only
+// recalculated if the size or modification time of the file changed since the last call.
+func digestFileCached(path string, info fs.FileInfo) (string, error) {
+	if cached, ok := contextFileDigestCache.Load(path); ok {
+		cachedDigest := cached.(contextFileDigest)
+		if cachedDigest.size == info.Size() && cachedDigest.modTime.Equal(info.ModTime()) {
+			return cachedDigest.digest, nil
+		}
+	}
+
+	digest, err := digestFile(path)
+	if err != nil {
+		return "", err
+	}
+	contextFileDigestCache.Store(path, contextFileDigest{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		digest:  digest,
+	})
+	return digest, nil
+}
+
+func digestFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() // nolint:errcheck
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// resourceDockerImageCustomizeDiff validates the ssh entries of the build and forces a rebuild
+// of the image when the hash of the build context differs from the one which was recorded
+// when the image was built. Images loaded from a changed source archive are reloaded.
+func resourceDockerImageCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateBuildSSH(d); err != nil {
+		return err
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	if err := diffSourceArchiveChecksum(ctx, d); err != nil {
+		return err
+	}
+
+	oldBuild, newBuild := d.GetChange("build")
+	oldBuilds := oldBuild.(*schema.Set).List()
+	newBuilds := newBuild.(*schema.Set).List()
+	if len(oldBuilds) == 0 || len(newBuilds) == 0 {
+		return nil
+	}
+
+	oldContextHash, _ := oldBuilds[0].(map[string]interface{})["context_hash"].(string)
+	if oldContextHash == "" {
+		// images built before the context hash was introduced
+		return nil
+	}
+
+	rawBuild := newBuilds[0].(map[string]interface{})
+	if contextPath, _ := rawBuild["context"].(string); contextPath == "" {
+		return nil
+	}
+
+	contextHash, err := calculateBuildContextHash(ctx, rawBuild)
+	if err != nil {
+		// the context might be created during the apply, e.g. by another resource
+		log.Printf("[WARN] unable to calculate build context hash: %v", err)
+		return nil
+	}
+
+	if contextHash == oldContextHash {
+		return nil
+	}
+
+	log.Printf("[DEBUG] build context hash changed from %s to %s, forcing a rebuild", oldContextHash, contextHash)
+	if err := d.SetNewComputed("image_id"); err != nil {
+		return err
+	}
+	return d.ForceNew("image_id")
+}
+
+// setBuildContextHash stores the hash of the build context the image was built from
+// in the build block.
+func setBuildContextHash(ctx context.Context, d *schema.ResourceData) error {
+	value, ok := d.GetOk("build")
+	if !ok {
+		return nil
+	}
+
+	builds := value.(*schema.Set).List()
+	for _, rawBuild := range builds {
+		rawBuildValue := rawBuild.(map[string]interface{})
+		contextHash, err := calculateBuildContextHash(ctx, rawBuildValue)
+		if err != nil {
+			return err
+		}
+		rawBuildValue["context_hash"] = contextHash
+	}
+
+	return d.Set("build", builds)
+}
@@ -0,0 +1,359 @@
+package provider
+
+// based on https://github.com/docker/buildx/blob/master/commands/bake.go and modified
+// to fit the needs of the provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/docker/buildx/bake"
+	"github.com/docker/buildx/bake/hclparser"
+	"github.com/docker/buildx/build"
+	"github.com/docker/buildx/builder"
+	"github.com/docker/buildx/util/confutil"
+	"github.com/docker/buildx/util/dockerutil"
+	"github.com/docker/buildx/util/progress"
+	"github.com/docker/cli/cli/command"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/util/progress/progressui"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type bakeOptions struct {
+	files     []string
+	targets   []string
+	overrides []string
+	variables map[string]string
+	allow     []string
+	builder   string
+}
+
+// bakeTargetResult is the result of a single target of a bake run.
+type bakeTargetResult struct {
+	imageDigest string
+	metadata    string
+}
+
+// readBakeTargets reads the bake files and resolves the given targets and groups with
+// the overrides and variables applied, in the same way as `docker buildx bake --print`.
+func readBakeTargets(ctx context.Context, options bakeOptions) (map[string]*bake.Target, []bake.File, error) {
+	files, err := bake.ReadLocalFiles(options.files, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, errors.New("couldn't find a bake definition")
+	}
+
+	defaults := map[string]string{
+		"BAKE_CMD_CONTEXT":    "cwd://",
+		"BAKE_LOCAL_PLATFORM": platforms.Format(platforms.DefaultSpec()),
+	}
+
+	targets := options.targets
+	if len(targets) == 0 {
+		targets = []string{"default"}
+	}
+
+	ent, err := bakeEntitlements(options.allow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variablesFile, err := bakeVariablesFile(files, defaults, options.variables)
+	if err != nil {
+		return nil, nil, err
+	}
+	readFiles := files
+	if variablesFile != nil {
+		readFiles = append(append([]bake.File{}, files...), *variablesFile)
+	}
+
+	// ReadTargets sanitizes the target names in place
+	tgts, _, err := bake.ReadTargets(ctx, readFiles, append([]string{}, targets...), options.overrides, defaults, &ent)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tgts, files, nil
+}
+
+// bakeVariablesFile returns a bake file which declares the variables again with the given
+// values as defaults. The bake parser uses the last declaration of a variable, so the
+// values take precedence over the defaults of the bake files. They are converted to the
+// type of the declared default in the same way as environment variables are converted by
+// the buildx CLI. Defaults which cannot be evaluated without the other variables are
+// treated as strings.
+func bakeVariablesFile(files []bake.File, defaults map[string]string, variables map[string]string) (*bake.File, error) {
+	if len(variables) == 0 {
+		return nil, nil
+	}
+
+	types, err := bakeVariableTypes(files, defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	var undeclared []string
+	for name := range variables {
+		if _, declared := types[name]; !declared {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) > 0 {
+		sort.Strings(undeclared)
+		return nil, errors.Errorf("the variables %s are not declared in the bake files", strings.Join(undeclared, ", "))
+	}
+
+	declarations := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		var typed interface{}
+		switch t := types[name]; {
+		case t.Equals(cty.Bool):
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse %s as bool", name)
+			}
+			typed = b
+		case t.Equals(cty.Number):
+			n, err := strconv.ParseFloat(value, 64)
+			if err == nil && (math.IsNaN(n) || math.IsInf(n, 0)) {
+				err = errors.Errorf("invalid number value")
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse %s as number", name)
+			}
+			typed = json.Number(value)
+		case t.Equals(cty.String), t.Equals(cty.DynamicPseudoType):
+			// strings of JSON bake files are templates, so the template sequences are escaped
+			typed = strings.NewReplacer("${", "$${", "%{", "%%{").Replace(value)
+		default:
+			return nil, errors.Errorf("unsupported type %s for variable %s", t.FriendlyName(), name)
+		}
+		declarations[name] = map[string]interface{}{"default": typed}
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"variable": declarations})
+	if err != nil {
+		return nil, err
+	}
+	return &bake.File{Name: "terraform-variables.json", Data: data}, nil
+}
+
+// bakeVariableTypes returns the type of the default value of every variable declared in
+// the bake files. Variables without a default or with a default which cannot be evaluated
+// on its own have the dynamic type.
+func bakeVariableTypes(files []bake.File, defaults map[string]string) (map[string]cty.Type, error) {
+	variableSchema := &hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "variable", LabelNames: []string{"name"}}},
+	}
+	defaultSchema := &hcl.BodySchema{
+		Attributes: []hcl.AttributeSchema{{Name: "default"}},
+	}
+	ectx := &hcl.EvalContext{Functions: hclparser.Stdlib()}
+
+	types := map[string]cty.Type{}
+	for _, file := range files {
+		hclFile, isHCL, err := bake.ParseHCLFile(file.Data, file.Name)
+		if !isHCL {
+			// compose files do not declare variables
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		content, _, diags := hclFile.Body.PartialContent(variableSchema)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		for _, block := range content.Blocks {
+			name := block.Labels[0]
+			if _, builtin := defaults[name]; builtin {
+				continue
+			}
+			types[name] = cty.DynamicPseudoType
+			attributes, _, diags := block.Body.PartialContent(defaultSchema)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			if attribute, ok := attributes.Attributes["default"]; ok {
+				if value, diags := attribute.Expr.Value(ectx); !diags.HasErrors() && value.IsWhollyKnown() {
+					types[name] = value.Type()
+				}
+			}
+		}
+	}
+	return types, nil
+}
+
+// bakeEntitlements parses the granted entitlements. Filesystem access below the current
+// working directory is allowed by default, the same as for the buildx CLI.
+func bakeEntitlements(allow []string) (bake.EntitlementConf, error) {
+	ent, err := bake.ParseEntitlements(allow)
+	if err != nil {
+		return ent, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return ent, errors.Wrapf(err, "failed to get current working directory")
+	}
+	ent.FSRead = append(ent.FSRead, wd)
+	ent.FSWrite = append(ent.FSWrite, wd)
+	return ent, nil
+}
+
+// calculateBakeHash calculates a sha256 hash over the bake files and the build context,
+// Dockerfile and build args of every resolved target. Remote contexts are only taken
+// into account by their reference.
+func calculateBakeHash(ctx context.Context, files []bake.File, targets map[string]*bake.Target) (string, error) {
+	h := sha256.New()
+
+	sortedFiles := append([]bake.File{}, files...)
+	sort.Slice(sortedFiles, func(i, j int) bool { return sortedFiles[i].Name < sortedFiles[j].Name })
+	for _, file := range sortedFiles {
+		fmt.Fprintf(h, "file\x00%s\x00%x\n", file.Name, sha256.Sum256(file.Data)) // nolint:errcheck
+	}
+
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := targets[name]
+		definition, err := json.Marshal(target)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "target\x00%s\x00%x\n", name, sha256.Sum256(definition)) // nolint:errcheck
+
+		contextPath := "."
+		if target.Context != nil {
+			contextPath = *target.Context
+		}
+		contextPath = strings.TrimPrefix(contextPath, "cwd://")
+		if build.IsRemoteURL(contextPath) || target.DockerfileInline != nil {
+			continue
+		}
+
+		rawBuild := map[string]interface{}{
+			"context": contextPath,
+		}
+		if target.Dockerfile != nil {
+			rawBuild["dockerfile"] = *target.Dockerfile
+		}
+		buildArgs := make(map[string]interface{}, len(target.Args))
+		for key, value := range target.Args {
+			if value != nil {
+				buildArgs[key] = *value
+			}
+		}
+		rawBuild["build_args"] = buildArgs
+
+		contextHash, err := calculateBuildContextHash(ctx, rawBuild)
+		if err != nil {
+			return "", fmt.Errorf("target %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "context\x00%s\x00%s\n", name, contextHash) // nolint:errcheck
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// runBake builds the resolved targets in one build session, so the targets share the
+// cache and are built in parallel.
+func runBake(ctx context.Context, dockerCli command.Cli, options bakeOptions, targets map[string]*bake.Target, buildLogFile string) (map[string]bakeTargetResult, error) {
+	if buildLogFile == "" {
+		buildLogFile = os.DevNull
+	}
+
+	logFile, err := os.OpenFile(buildLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open build log file: %w", err)
+	}
+	defer logFile.Close() // nolint:errcheck
+
+	contextPathHash, _ := os.Getwd()
+	b, err := builder.New(dockerCli,
+		builder.WithName(options.builder),
+		builder.WithContextPathHash(contextPathHash),
+	)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := b.LoadNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bo, err := bake.TargetsToBuildOpt(targets, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ent, err := bakeEntitlements(options.allow)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := ent.Validate(bo)
+	if err != nil {
+		return nil, err
+	}
+	var promptOut bytes.Buffer
+	if err := exp.Prompt(ctx, false, &promptOut); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(promptOut.String()))
+	}
+
+	ctx2, cancel := context.WithCancelCause(context.TODO())
+	defer func() { cancel(errors.WithStack(context.Canceled)) }()
+	progressMode := progressui.PlainMode
+	var printer *progress.Printer
+	printer, err = progress.NewPrinter(ctx2, logFile, progressMode,
+		progress.WithDesc(
+			fmt.Sprintf("building with %q instance using %s driver", b.Name, b.Driver),
+			fmt.Sprintf("%s:%s", b.Driver, b.Name),
+		),
+		progress.WithOnClose(func() {
+			printWarnings(logFile, printer.Warnings(), progressMode)
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, retErr := build.Build(ctx, nodes, bo, dockerutil.NewClient(dockerCli), confutil.NewConfig(dockerCli), printer)
+	if err := printer.Wait(); retErr == nil {
+		retErr = err
+	}
+	if retErr != nil {
+		return nil, retErr
+	}
+
+	results := make(map[string]bakeTargetResult, len(resp))
+	for name, r := range resp {
+		metadata, err := json.Marshal(decodeExporterResponse(r.ExporterResponse))
+		if err != nil {
+			return nil, err
+		}
+		results[name] = bakeTargetResult{
+			imageDigest: r.ExporterResponse[exptypes.ExporterImageDigestKey],
+			metadata:    string(metadata),
+		}
+		log.Printf("[DEBUG] bake target %s built with digest %q", name, results[name].imageDigest)
+	}
+	return results, nil
+}
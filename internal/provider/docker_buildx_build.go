@@ -121,6 +121,16 @@ type ResolvedBuilder struct {
 	NG     *store.NodeGroup // may be a synthetic “context builder” nodegroup
 }
 
+// buildResult holds the metadata of a finished buildx build
+type buildResult struct {
+	imageDigest   string
+	configDigest  string
+	buildRef      string
+	warnings      []string
+	builderName   string
+	builderSource BuilderSource
+}
+
 func (o *buildOptions) toControllerOptions() (*controllerapi.BuildOptions, error) {
 	var err error
 
@@ -292,6 +302,21 @@ func mapBuildAttributesToBuildOptions(buildAttributes map[string]interface{}, im
 		}
 	}
 
+	if sshRaw, ok := buildAttributes["ssh"].([]interface{}); ok {
+		agentConfigs, err := parseBuildSSH(sshRaw)
+		if err != nil {
+			return options, err
+		}
+		for _, agentConfig := range agentConfigs {
+			// Construct the ssh string in the format <ID>[=<socket>|<key>[,<key>]]
+			sshStr := agentConfig.ID
+			if len(agentConfig.Paths) > 0 {
+				sshStr += "=" + strings.Join(agentConfig.Paths, ",")
+			}
+			options.ssh = append(options.ssh, sshStr)
+		}
+	}
+
 	if labels, ok := buildAttributes["label"].(map[string]interface{}); ok {
 		for key, value := range labels {
 			if valueStr, ok := value.(string); ok {
@@ -506,7 +531,7 @@ func resolveByName(dockerCli command.Cli, name string, src BuilderSource) (Resol
 	return ResolvedBuilder{Name: ng.Name, Source: src, NG: ng}, nil
 }
 
-func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions, buildLogFile string) (err error) {
+func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions, buildLogFile string) (result *buildResult, err error) {
 
 	if buildLogFile == "" {
 		buildLogFile = os.DevNull
@@ -514,7 +539,7 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions,
 
 	logFile, err := os.OpenFile(buildLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to open build log file: %w", err)
+		return nil, fmt.Errorf("failed to open build log file: %w", err)
 	}
 	defer logFile.Close() // nolint:errcheck
 
@@ -522,13 +547,13 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions,
 
 	opts, err := options.toControllerOptions()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Avoid leaving a stale file if we eventually fail
 	if options.imageIDFile != "" {
 		if err := os.Remove(options.imageIDFile); err != nil && !os.IsNotExist(err) {
-			return errors.Wrap(err, "removing image ID file")
+			return nil, errors.Wrap(err, "removing image ID file")
 		}
 	}
 
@@ -541,11 +566,19 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions,
 		builder.WithContextPathHash(contextPathHash),
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	_, err = b.LoadNodes(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	result = &buildResult{builderName: b.Name}
+	if resolvedBuilder, err := ResolveBuilderLikeBuildx(ctx, dockerCli, options.builder); err == nil {
+		result.builderName = resolvedBuilder.Name
+		result.builderSource = resolvedBuilder.Source
+	} else {
+		log.Printf("[WARN] unable to resolve source of builder %q: %v", b.Name, err)
 	}
 
 	var term bool
@@ -557,7 +590,7 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions,
 	defer func() { cancel(errors.WithStack(context.Canceled)) }()
 	progressMode := progressui.PlainMode
 	if err != nil {
-		return err
+		return nil, err
 	}
 	var printer *progress.Printer
 	printer, err = progress.NewPrinter(ctx2, logFile, progressMode,
@@ -571,7 +604,7 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions,
 	)
 	if err != nil {
 		logger.Printf("error creating progress printer: %v", err)
-		return err
+		return nil, err
 	}
 
 	var resp *client.SolveResponse
@@ -590,14 +623,14 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions,
 	}
 
 	if retErr != nil {
-		return retErr
+		return nil, retErr
 	}
 
 	desktop.PrintBuildDetails(logFile, printer.BuildRefs(), term)
 
 	if options.imageIDFile != "" {
 		if err := os.WriteFile(options.imageIDFile, []byte(getImageID(resp.ExporterResponse)), 0644); err != nil {
-			return errors.Wrap(err, "writing image ID file")
+			return nil, errors.Wrap(err, "writing image ID file")
 		}
 	}
 	if options.metadataFile != "" {
@@ -608,21 +641,69 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions,
 			}
 		}
 		if err := writeMetadataFile(options.metadataFile, dt); err != nil {
-			return err
+			return nil, err
 		}
 	}
 	if opts.CallFunc != nil {
 		if exitcode, err := printResult(dockerCli.Out(), opts.CallFunc, resp.ExporterResponse, options.target, inputs); err != nil {
-			return err
+			return nil, err
 		} else if exitcode != 0 {
 			os.Exit(exitcode)
 		}
 	}
 	if v, ok := resp.ExporterResponse["frontend.result.inlinemessage"]; ok {
 		fmt.Fprintf(dockerCli.Out(), "\n%s\n", v) // nolint:errcheck
-		return nil
 	}
-	return nil
+
+	result.imageDigest = resp.ExporterResponse[exptypes.ExporterImageDigestKey]
+	result.configDigest = resp.ExporterResponse[exptypes.ExporterImageConfigDigestKey]
+	result.buildRef = resp.ExporterResponse["buildx.build.ref"]
+	for _, warning := range printer.Warnings() {
+		result.warnings = append(result.warnings, string(warning.Short))
+	}
+	return result, nil
+}
+
+// runBuildCall runs a build with the given frontend subrequest (e.g. `check` or `targets`)
+// instead of building the image and returns the raw exporter response.
+func runBuildCall(ctx context.Context, dockerCli command.Cli, options buildOptions, callFunc string) (map[string]string, *build.Inputs, error) {
+	options.callFunc = callFunc
+	options.exportLoad = false
+
+	opts, err := options.toControllerOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b, err := builder.New(dockerCli, builder.WithName(options.builder))
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := b.LoadNodes(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer devNull.Close() // nolint:errcheck
+
+	ctx2, cancel := context.WithCancelCause(context.TODO())
+	defer func() { cancel(errors.WithStack(context.Canceled)) }()
+	printer, err := progress.NewPrinter(ctx2, devNull, progressui.QuietMode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, inputs, retErr := runBasicBuild(ctx, dockerCli, opts, printer)
+	if err := printer.Wait(); retErr == nil {
+		retErr = err
+	}
+	if retErr != nil {
+		return nil, nil, retErr
+	}
+	return resp.ExporterResponse, inputs, nil
 }
 
 func writeMetadataFile(filename string, dt any) error {
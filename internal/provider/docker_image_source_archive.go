@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/moby/go-archive"
+	"github.com/moby/go-archive/compression"
+)
+
+// loadImageSourceArchive loads the image from the source archive of the resource and makes
+// sure it is available as imageName. The checksum of the archive is recorded in the
+// source_archive block, so that a change of the archive can be detected.
+func loadImageSourceArchive(ctx context.Context, d *schema.ResourceData, client *client.Client, imageName string) error {
+	sourceArchives := d.Get("source_archive").([]interface{})
+	if len(sourceArchives) == 0 || sourceArchives[0] == nil {
+		return nil
+	}
+	rawSourceArchive := sourceArchives[0].(map[string]interface{})
+	archivePath := rawSourceArchive["path"].(string)
+
+	checksum, err := calculateSourceArchiveChecksum(ctx, archivePath)
+	if err != nil {
+		return fmt.Errorf("unable to calculate checksum of %s: %w", archivePath, err)
+	}
+
+	loadedImages, err := loadImageArchive(ctx, client, archivePath, rawSourceArchive["platform"].(string))
+	if err != nil {
+		return fmt.Errorf("unable to load %s: %w", archivePath, err)
+	}
+	log.Printf("[DEBUG] loaded images %v from %s", loadedImages, archivePath)
+
+	imageInspect, err := client.ImageInspect(ctx, imageName)
+	if errdefs.IsNotFound(err) {
+		// archives without a matching reference, e.g. OCI layouts without the
+		// org.opencontainers.image.ref.name annotation, only provide the image ID
+		if len(loadedImages) != 1 {
+			return fmt.Errorf("%s does not contain the image %s, it contains %v", archivePath, imageName, loadedImages)
+		}
+		log.Printf("[DEBUG] tagging loaded image %s as %s", loadedImages[0], imageName)
+		if err := client.ImageTag(ctx, loadedImages[0], imageName); err != nil {
+			return fmt.Errorf("unable to tag loaded image %s as %s: %w", loadedImages[0], imageName, err)
+		}
+		imageInspect, err = client.ImageInspect(ctx, imageName)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to inspect image %s: %w", imageName, err)
+	}
+
+	if digest := rawSourceArchive["digest"].(string); digest != "" && !imageMatchesDigest(imageInspect, digest) {
+		return fmt.Errorf("the image %s loaded from %s has the ID %s, which does not match the expected digest %s", imageName, archivePath, imageInspect.ID, digest)
+	}
+
+	rawSourceArchive["checksum"] = checksum
+	return d.Set("source_archive", []interface{}{rawSourceArchive})
+}
+
+// loadImageArchive loads an image tar archive, or an OCI layout directory, into the daemon and
+// returns the references of the loaded images. Images without a tag are returned by their ID.
+func loadImageArchive(ctx context.Context, dockerClient *client.Client, archivePath string, platform string) ([]string, error) {
+	archiveReader, err := openSourceArchive(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer archiveReader.Close() // nolint:errcheck
+
+	var loadOptions []client.ImageLoadOption
+	parsedPlatform, err := parseOptionalPlatform(platform)
+	if err != nil {
+		return nil, fmt.Errorf("invalid platform %q: %w", platform, err)
+	}
+	if parsedPlatform != nil {
+		loadOptions = append(loadOptions, client.ImageLoadWithPlatforms(*parsedPlatform))
+	}
+
+	loadResponse, err := dockerClient.ImageLoad(ctx, archiveReader, loadOptions...)
+	if err != nil {
+		return nil, err
+	}
+	defer loadResponse.Body.Close() // nolint:errcheck
+
+	if !loadResponse.JSON {
+		output, err := io.ReadAll(loadResponse.Body)
+		if err != nil {
+			return nil, err
+		}
+		return parseLoadedImages(string(output)), nil
+	}
+	return parseImageLoadOutput(loadResponse.Body)
+}
+
+// openSourceArchive opens the tar archive at archivePath, which is decompressed if it is
+// compressed with gzip, bzip2, xz or zstd. A directory, such as an OCI layout, is packed
+// into a tar archive on the fly.
+func openSourceArchive(archivePath string) (io.ReadCloser, error) {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return archive.TarWithOptions(archivePath, &archive.TarOptions{})
+	}
+
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	decompressed, err := compression.DecompressStream(archiveFile)
+	if err != nil {
+		archiveFile.Close() // nolint:errcheck
+		return nil, fmt.Errorf("unable to decompress %s: %w", archivePath, err)
+	}
+	return &decompressedArchive{ReadCloser: decompressed, file: archiveFile}, nil
+}
+
+// decompressedArchive closes the archive file together with the decompressed stream.
+type decompressedArchive struct {
+	io.ReadCloser
+	file *os.File
+}
+
+func (a *decompressedArchive) Close() error {
+	return errors.Join(a.ReadCloser.Close(), a.file.Close())
+}
+
+// parseImageLoadOutput returns the references of the images listed in the JSON message
+// stream returned by the daemon when loading images.
+func parseImageLoadOutput(output io.Reader) ([]string, error) {
+	var loadedImages []string
+	decoder := json.NewDecoder(output)
+	for {
+		var message jsonmessage.JSONMessage
+		if err := decoder.Decode(&message); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("unable to decode the output of the image load: %w", err)
+		}
+		if message.Error != nil {
+			return nil, message.Error
+		}
+
+		loadedImages = append(loadedImages, parseLoadedImages(message.Stream)...)
+	}
+	return loadedImages, nil
+}
+
+// parseLoadedImages returns the references of the images listed in the output lines of an image load.
+func parseLoadedImages(output string) []string {
+	var loadedImages []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if loadedImage, ok := strings.CutPrefix(line, "Loaded image ID: "); ok {
+			loadedImages = append(loadedImages, loadedImage)
+		} else if loadedImage, ok := strings.CutPrefix(line, "Loaded image: "); ok {
+			loadedImages = append(loadedImages, loadedImage)
+		}
+	}
+	return loadedImages
+}
+
+// calculateSourceArchiveChecksum calculates the sha256 checksum of the archive file at
+// archivePath, or a sha256 hash over all files if archivePath is a directory.
+func calculateSourceArchiveChecksum(ctx context.Context, archivePath string) (string, error) {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return digestFileCached(archivePath, info)
+	}
+
+	entries, err := walkBuildContext(archivePath, nil)
+	if err != nil {
+		return "", err
+	}
+	if err := digestBuildContextEntries(ctx, entries); err != nil {
+		return "", err
+	}
+
+	return sumContextHashEntries(entries), nil
+}
+
+// imageMatchesDigest returns whether the image has the given ID, or is referenced by the
+// given manifest digest, which is the ID of the image when the containerd image store is used.
+func imageMatchesDigest(imageInspect image.InspectResponse, digest string) bool {
+	if imageInspect.ID == digest {
+		return true
+	}
+	if imageInspect.Descriptor != nil && imageInspect.Descriptor.Digest.String() == digest {
+		return true
+	}
+	for _, repoDigest := range imageInspect.RepoDigests {
+		if strings.HasSuffix(repoDigest, "@"+digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSourceArchiveDigest checks that name still refers to the image with the expected digest
+// of the source archive. Otherwise the recorded checksum is cleared, so that the image is reloaded.
+func checkSourceArchiveDigest(ctx context.Context, d *schema.ResourceData, client *client.Client, imageName string) error {
+	rawSourceArchive := d.Get("source_archive").([]interface{})[0].(map[string]interface{})
+	digest := rawSourceArchive["digest"].(string)
+	if digest == "" {
+		return nil
+	}
+
+	imageInspect, err := client.ImageInspect(ctx, imageName)
+	if err != nil {
+		return err
+	}
+	if imageMatchesDigest(imageInspect, digest) {
+		return nil
+	}
+
+	log.Printf("[DEBUG] image %s has the ID %s instead of the expected digest %s, it will be reloaded", imageName, imageInspect.ID, digest)
+	rawSourceArchive["checksum"] = ""
+	return d.Set("source_archive", []interface{}{rawSourceArchive})
+}
+
+// diffSourceArchiveChecksum plans a reload of the image if the checksum of the source archive
+// differs from the one which was recorded when the image was loaded.
+func diffSourceArchiveChecksum(ctx context.Context, d *schema.ResourceDiff) error {
+	oldSourceArchive, newSourceArchive := d.GetChange("source_archive")
+	oldSourceArchives := oldSourceArchive.([]interface{})
+	newSourceArchives := newSourceArchive.([]interface{})
+	if len(oldSourceArchives) == 0 || oldSourceArchives[0] == nil || len(newSourceArchives) == 0 || newSourceArchives[0] == nil {
+		return nil
+	}
+
+	archivePath, _ := newSourceArchives[0].(map[string]interface{})["path"].(string)
+	if archivePath == "" {
+		return nil
+	}
+
+	oldChecksum, _ := oldSourceArchives[0].(map[string]interface{})["checksum"].(string)
+	if oldChecksum != "" {
+		checksum, err := calculateSourceArchiveChecksum(ctx, archivePath)
+		if err != nil {
+			// the archive might be created during the apply, e.g. by another resource
+			log.Printf("[WARN] unable to calculate checksum of %s: %v", archivePath, err)
+			return nil
+		}
+		if checksum == oldChecksum {
+			return nil
+		}
+		log.Printf("[DEBUG] checksum of %s changed from %s to %s, reloading the image", archivePath, oldChecksum, checksum)
+	} else {
+		log.Printf("[DEBUG] image loaded from %s is not present anymore, reloading it", archivePath)
+	}
+
+	if err := d.SetNewComputed("image_id"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("repo_digest")
+}
@@ -237,6 +237,8 @@ func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Res
 func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewDockerContainersDataSource,
+		NewDockerContainerFileDataSource,
+		NewDockerImagesDataSource,
 		NewDockerRegistryImageTagsDataSource,
 	}
 }
@@ -255,11 +257,17 @@ func (p *frameworkProvider) Actions(ctx context.Context) []func() action.Action
 		func() action.Action {
 			return &DockerContainerExportAction{}
 		},
+		func() action.Action {
+			return &DockerContainerCommitAction{}
+		},
 		func() action.Action {
 			return &DockerExecAction{}
 		},
 		func() action.Action {
 			return &DockerSystemPruneAction{}
 		},
+		func() action.Action {
+			return &DockerBuilderPruneAction{}
+		},
 	}
 }
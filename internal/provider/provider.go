@@ -180,6 +180,8 @@ func New(version string) func() *schema.Provider {
 				"docker_plugin":         resourceDockerPlugin(),
 				"docker_tag":            resourceDockerTag(),
 				"docker_buildx_builder": resourceDockerBuildxBuilder(),
+				"docker_buildx_build":   resourceDockerBuildxBuild(),
+				"docker_buildx_bake":    resourceDockerBuildxBake(),
 			},
 
 			DataSourcesMap: map[string]*schema.Resource{
@@ -189,6 +191,9 @@ func New(version string) func() *schema.Provider {
 				"docker_image":                    dataSourceDockerImage(),
 				"docker_logs":                     dataSourceDockerLogs(),
 				"docker_registry_image_manifests": dataSourceDockerRegistryImageManifests(),
+				"docker_dockerfile_check":         dataSourceDockerDockerfileCheck(),
+				"docker_build_cache":              dataSourceDockerBuildCache(),
+				"docker_buildx_builders":          dataSourceDockerBuildxBuilders(),
 			},
 		}
 
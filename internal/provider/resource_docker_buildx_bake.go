@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dockerBuildxBakeCreateDefaultTimeout = 60 * time.Minute
+)
+
+// resourceDockerBuildxBake defines the buildx_bake resource schema
+func resourceDockerBuildxBake() *schema.Resource {
+	return &schema.Resource{
+		Description: "Builds the targets of a bake definition with `docker buildx bake`. All targets are built in a single build session, so they share the build cache and are built in parallel. Please see https://docs.docker.com/build/bake/ for more information about bake files. Destroying the resource does not remove the built images.",
+
+		CreateContext: resourceDockerBuildxBakeCreate,
+		ReadContext:   resourceDockerBuildxBakeRead,
+		DeleteContext: resourceDockerBuildxBakeDelete,
+		CustomizeDiff: resourceDockerBuildxBakeCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(dockerBuildxBakeCreateDefaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"files": {
+				Type:        schema.TypeList,
+				Description: "The bake files, e.g. `docker-bake.hcl` or `compose.yaml`. Relative paths and the contexts of the targets are resolved relative to the current working directory. If not set, the default files of the buildx CLI are read from the current working directory.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"targets": {
+				Type:        schema.TypeList,
+				Description: "The targets and groups to build. Defaults to the `default` group.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"set": {
+				Type:        schema.TypeList,
+				Description: "Overrides of target attributes in the form of `targetpattern.key=value`, e.g. `*.platform=linux/arm64`. Please see https://docs.docker.com/reference/cli/docker/buildx/bake/#set for more information.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateStringMatchesPattern(`^[^=.]+\.[^=]+=.*$`),
+				},
+			},
+			"variables": {
+				Type:        schema.TypeMap,
+				Description: "Values of the variables declared in the bake files. They take precedence over the defaults in the bake files and are converted to the type of the default value like environment variables are. Environment variables of the provider with the same name still take precedence, as for `docker buildx bake`.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"allow": {
+				Type:        schema.TypeList,
+				Description: "Privileges granted to the build, e.g. `network.host` or `fs.read=/path`. Please see https://docs.docker.com/reference/cli/docker/buildx/bake/#allow for more information.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"push": {
+				Type:        schema.TypeBool,
+				Description: "Push the images of all targets to their registry. Shorthand for `set = [\"*.push=true\"]`.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"load": {
+				Type:        schema.TypeBool,
+				Description: "Load the images of all targets into the Docker image store. Shorthand for `set = [\"*.load=true\"]`.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"no_cache": {
+				Type:        schema.TypeBool,
+				Description: "Do not use the cache when building.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"pull": {
+				Type:        schema.TypeBool,
+				Description: "Always attempt to pull all referenced images.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"builder": {
+				Type:        schema.TypeString,
+				Description: "The name of the buildx builder to use. If BUILDX_BUILDER environment variable is set, it will be used. If left empty, the provider resolves the builder the same way as the buildx CLI.",
+				Optional:    true,
+				ForceNew:    true,
+				DefaultFunc: schema.EnvDefaultFunc("BUILDX_BUILDER", ""),
+			},
+			"build_log_file": {
+				Type:        schema.TypeString,
+				Description: "Path to a file where the buildx log are written to. If not set, no logs are available.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"triggers": {
+				Description: "A map of arbitrary strings that, when changed, will force the targets to be built again.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"bake_hash": {
+				Type:        schema.TypeString,
+				Description: "The sha256 hash of the bake files and of the build context, Dockerfile and build args of every target. A change of the hash builds the targets again.",
+				Computed:    true,
+			},
+			"image_digests": {
+				Type:        schema.TypeMap,
+				Description: "A map of target name to the digest of the built image. The digest is empty for exporters which do not create an image.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"metadata": {
+				Type:        schema.TypeMap,
+				Description: "A map of target name to the JSON encoded build metadata of the target, the same as written by `docker buildx bake --metadata-file`. Use `jsondecode` to access e.g. `containerimage.config.digest` or `image.name`.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceDockerBuildxBakeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create Docker client: %w", err))
+	}
+
+	options := mapBuildxBakeAttributesToBakeOptions(d.Get)
+	targets, files, err := readBakeTargets(ctx, options)
+	if err != nil {
+		return diag.Errorf("Error reading bake definition: %v", err)
+	}
+
+	bakeHash, err := calculateBakeHash(ctx, files, targets)
+	if err != nil {
+		return diag.Errorf("Unable to calculate bake hash: %s", err)
+	}
+
+	dockerCli, err := createAndInitDockerCli(client)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create and init Docker CLI: %w", err))
+	}
+
+	if options.builder == "" {
+		resolvedBuilder, err := ResolveBuilderLikeBuildx(ctx, dockerCli, "")
+		if err != nil {
+			return diag.Errorf("error resolving default builder: %v", err)
+		}
+		options.builder = resolvedBuilder.Name
+	}
+
+	log.Printf("[DEBUG] bake options %#v", options)
+
+	results, err := runBake(ctx, dockerCli, options, targets, d.Get("build_log_file").(string))
+	if err != nil {
+		return diag.Errorf("Error running buildx bake: %v", err)
+	}
+
+	imageDigests := make(map[string]interface{}, len(results))
+	metadata := make(map[string]interface{}, len(results))
+	for name, result := range results {
+		imageDigests[name] = result.imageDigest
+		metadata[name] = result.metadata
+	}
+
+	d.SetId(bakeHash)
+	d.Set("bake_hash", bakeHash)
+	d.Set("image_digests", imageDigests)
+	d.Set("metadata", metadata)
+
+	return resourceDockerBuildxBakeRead(ctx, d, meta)
+}
+
+func resourceDockerBuildxBakeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// the built images can be pushed to registries or exported to files, so there
+	// is nothing which can be checked reliably. Changes are detected via the bake hash.
+	return nil
+}
+
+func resourceDockerBuildxBakeDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// the built images are kept on purpose, they are the artifacts of the build
+	d.SetId("")
+	return nil
+}
+
+// resourceDockerBuildxBakeCustomizeDiff builds the targets again when the bake files or
+// the build context of one of the targets changed.
+func resourceDockerBuildxBakeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	oldBakeHash := d.Get("bake_hash").(string)
+	if oldBakeHash == "" {
+		return nil
+	}
+
+	targets, files, err := readBakeTargets(ctx, mapBuildxBakeAttributesToBakeOptions(d.Get))
+	if err != nil {
+		// the bake files might be created during the apply, e.g. by another resource
+		log.Printf("[WARN] unable to read bake definition: %v", err)
+		return nil
+	}
+
+	bakeHash, err := calculateBakeHash(ctx, files, targets)
+	if err != nil {
+		log.Printf("[WARN] unable to calculate bake hash: %v", err)
+		return nil
+	}
+
+	if bakeHash == oldBakeHash {
+		return nil
+	}
+
+	log.Printf("[DEBUG] bake hash changed from %s to %s, building the targets again", oldBakeHash, bakeHash)
+	if err := d.SetNew("bake_hash", bakeHash); err != nil {
+		return err
+	}
+	return d.ForceNew("bake_hash")
+}
+
+func mapBuildxBakeAttributesToBakeOptions(get func(string) interface{}) bakeOptions {
+	options := bakeOptions{
+		files:     interfaceArrayToStringArray(get("files").([]interface{})),
+		targets:   interfaceArrayToStringArray(get("targets").([]interface{})),
+		overrides: interfaceArrayToStringArray(get("set").([]interface{})),
+		allow:     interfaceArrayToStringArray(get("allow").([]interface{})),
+		variables: map[string]string{},
+		builder:   get("builder").(string),
+	}
+
+	for name, value := range get("variables").(map[string]interface{}) {
+		options.variables[name] = value.(string)
+	}
+
+	for _, flag := range []string{"push", "load", "no_cache", "pull"} {
+		if get(flag).(bool) {
+			options.overrides = append(options.overrides, fmt.Sprintf("*.%s=true", strings.ReplaceAll(flag, "_", "-")))
+		}
+	}
+
+	return options
+}
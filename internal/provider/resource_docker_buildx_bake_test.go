@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testBakeFile = `variable "TAG" {
+  default = "latest"
+}
+
+variable "DIST" {
+  default = "dist"
+}
+
+group "default" {
+  targets = ["app", "tools"]
+}
+
+target "app" {
+  context = "%[1]s"
+  target  = "app"
+  tags    = ["example.com/app:${TAG}"]
+  output  = ["type=local,dest=${DIST}/app"]
+}
+
+target "tools" {
+  context = "%[1]s"
+  target  = "tools"
+  output  = ["type=local,dest=${DIST}/tools"]
+}
+`
+
+func writeTestBakeDefinition(t *testing.T) (string, string) {
+	t.Helper()
+
+	contextDir := t.TempDir()
+	dockerfile := "FROM scratch AS app\nCOPY app.txt /app.txt\n\nFROM scratch AS tools\nCOPY tools.txt /tools.txt\n"
+	files := map[string]string{
+		"Dockerfile": dockerfile,
+		"app.txt":    "app\n",
+		"tools.txt":  "tools\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(contextDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	bakeFile := filepath.Join(t.TempDir(), "docker-bake.hcl")
+	if err := os.WriteFile(bakeFile, []byte(fmt.Sprintf(testBakeFile, contextDir)), 0644); err != nil {
+		t.Fatalf("Failed to create bake file: %v", err)
+	}
+	return contextDir, bakeFile
+}
+
+func TestAccDockerBuildxBake_LocalOutput(t *testing.T) {
+	contextDir, bakeFile := writeTestBakeDefinition(t)
+	distDir := t.TempDir()
+
+	config := loadTestConfiguration(t, RESOURCE, "docker_buildx_bake", "testAccDockerBuildxBakeLocalOutput")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, bakeFile, distDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_buildx_bake.foo", "metadata.%", "2"),
+					resource.TestCheckResourceAttrSet("docker_buildx_bake.foo", "metadata.app"),
+					resource.TestCheckResourceAttrSet("docker_buildx_bake.foo", "metadata.tools"),
+					resource.TestMatchResourceAttr("docker_buildx_bake.foo", "bake_hash", regexp.MustCompile(`\A[a-f0-9]{64}\z`)),
+					func(*terraform.State) error {
+						for _, path := range []string{filepath.Join(distDir, "app", "app.txt"), filepath.Join(distDir, "tools", "tools.txt")} {
+							if _, err := os.Stat(path); err != nil {
+								return fmt.Errorf("expected %s to be exported: %w", path, err)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// a changed build context of one of the targets builds the targets again
+				PreConfig: func() {
+					if err := os.WriteFile(filepath.Join(contextDir, "tools.txt"), []byte("more tools\n"), 0644); err != nil {
+						t.Fatalf("Failed to update tools.txt: %v", err)
+					}
+				},
+				Config:             fmt.Sprintf(config, bakeFile, distDir),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestReadBakeTargets(t *testing.T) {
+	_, bakeFile := writeTestBakeDefinition(t)
+
+	options := bakeOptions{
+		files:     []string{bakeFile},
+		targets:   []string{"app"},
+		overrides: []string{"*.platform=linux/arm64"},
+		variables: map[string]string{"TAG": "1.0.0"},
+	}
+	targets, files, err := readBakeTargets(context.Background(), options)
+	if err != nil {
+		t.Fatalf("Unable to read bake targets: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("Expected 1 bake file, got %d", len(files))
+	}
+	if len(targets) != 1 {
+		t.Fatalf("Expected only the app target, got %v", targets)
+	}
+	app := targets["app"]
+	if len(app.Tags) != 1 || app.Tags[0] != "example.com/app:1.0.0" {
+		t.Errorf("Expected the TAG variable to be applied, got tags %v", app.Tags)
+	}
+	if len(app.Platforms) != 1 || app.Platforms[0] != "linux/arm64" {
+		t.Errorf("Expected the platform override to be applied, got %v", app.Platforms)
+	}
+
+	options.targets = nil
+	targets, _, err = readBakeTargets(context.Background(), options)
+	if err != nil {
+		t.Fatalf("Unable to read bake targets: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Errorf("Expected the default group to resolve to 2 targets, got %d", len(targets))
+	}
+}
+
+func TestReadBakeTargets_Variables(t *testing.T) {
+	bakeFile := filepath.Join(t.TempDir(), "docker-bake.hcl")
+	definition := `variable "DEBUG" {
+  default = false
+}
+
+variable "JOBS" {
+  default = 1
+}
+
+variable "LABEL" {
+  default = "none"
+}
+
+target "default" {
+  context = "."
+  args = {
+    DEBUG = equal(DEBUG, true) ? "on" : "off"
+    JOBS  = JOBS + 1
+    LABEL = LABEL
+  }
+}
+`
+	if err := os.WriteFile(bakeFile, []byte(definition), 0644); err != nil {
+		t.Fatalf("Failed to create bake file: %v", err)
+	}
+
+	options := bakeOptions{
+		files:     []string{bakeFile},
+		variables: map[string]string{"DEBUG": "true", "JOBS": "3", "LABEL": "${DEBUG}"},
+	}
+	targets, _, err := readBakeTargets(context.Background(), options)
+	if err != nil {
+		t.Fatalf("Unable to read bake targets: %v", err)
+	}
+	args := targets["default"].Args
+	if args["DEBUG"] == nil || *args["DEBUG"] != "on" {
+		t.Errorf("Expected DEBUG to be converted to a bool, got args %v", args)
+	}
+	if args["JOBS"] == nil || *args["JOBS"] != "4" {
+		t.Errorf("Expected JOBS to be converted to a number, got args %v", args)
+	}
+	if args["LABEL"] == nil || *args["LABEL"] != "${DEBUG}" {
+		t.Errorf("Expected LABEL to be passed as a literal string, got args %v", args)
+	}
+
+	options.variables = map[string]string{"JOBS": "many"}
+	if _, _, err := readBakeTargets(context.Background(), options); err == nil || !strings.Contains(err.Error(), "JOBS") {
+		t.Errorf("Expected an error about the invalid number JOBS, got %v", err)
+	}
+
+	options.variables = map[string]string{"DEBUG": "true", "UNKNOWN": "value"}
+	if _, _, err := readBakeTargets(context.Background(), options); err == nil || !strings.Contains(err.Error(), "UNKNOWN") {
+		t.Errorf("Expected an error about the undeclared variable UNKNOWN, got %v", err)
+	}
+}
+
+func TestCalculateBakeHash(t *testing.T) {
+	contextDir, bakeFile := writeTestBakeDefinition(t)
+	options := bakeOptions{files: []string{bakeFile}}
+
+	hash := func() string {
+		targets, files, err := readBakeTargets(context.Background(), options)
+		if err != nil {
+			t.Fatalf("Unable to read bake targets: %v", err)
+		}
+		bakeHash, err := calculateBakeHash(context.Background(), files, targets)
+		if err != nil {
+			t.Fatalf("Unable to calculate bake hash: %v", err)
+		}
+		return bakeHash
+	}
+
+	initial := hash()
+	if initial != hash() {
+		t.Errorf("Expected the bake hash to be stable")
+	}
+
+	options.variables = map[string]string{"TAG": "1.0.0"}
+	withVariable := hash()
+	if withVariable == initial {
+		t.Errorf("Expected a changed variable to change the bake hash")
+	}
+
+	if err := os.WriteFile(filepath.Join(contextDir, "app.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatalf("Failed to update app.txt: %v", err)
+	}
+	if hash() == withVariable {
+		t.Errorf("Expected a changed build context to change the bake hash")
+	}
+}
+
+func TestMapBuildxBakeAttributesToBakeOptions(t *testing.T) {
+	attributes := map[string]interface{}{
+		"files":     []interface{}{"docker-bake.hcl"},
+		"targets":   []interface{}{"app"},
+		"set":       []interface{}{"app.args.VERSION=1.0.0"},
+		"allow":     []interface{}{"network.host"},
+		"variables": map[string]interface{}{"TAG": "1.0.0"},
+		"builder":   "",
+		"push":      true,
+		"load":      false,
+		"no_cache":  true,
+		"pull":      false,
+	}
+
+	options := mapBuildxBakeAttributesToBakeOptions(func(key string) interface{} { return attributes[key] })
+	expectedOverrides := []string{"app.args.VERSION=1.0.0", "*.push=true", "*.no-cache=true"}
+	if fmt.Sprint(options.overrides) != fmt.Sprint(expectedOverrides) {
+		t.Errorf("Expected overrides %v, got %v", expectedOverrides, options.overrides)
+	}
+	if options.variables["TAG"] != "1.0.0" {
+		t.Errorf("Expected variable TAG to be 1.0.0, got %q", options.variables["TAG"])
+	}
+}
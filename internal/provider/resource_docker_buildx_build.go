@@ -0,0 +1,459 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	dockeropts "github.com/docker/cli/opts"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	dockerBuildxBuildCreateDefaultTimeout = 20 * time.Minute
+)
+
+// resourceDockerBuildxBuild defines the buildx_build resource schema
+func resourceDockerBuildxBuild() *schema.Resource {
+	return &schema.Resource{
+		Description: "Runs a buildx build and exports the result with the given exporters, e.g. to a local directory, a tarball or an OCI image layout. In contrast to `docker_image` the result is not loaded into the Docker image store. This is useful to compile artifacts with multi-stage Dockerfiles. Please see https://docs.docker.com/build/exporters/ for more information about exporters. Destroying the resource does not remove the exported files.",
+
+		CreateContext: resourceDockerBuildxBuildCreate,
+		ReadContext:   resourceDockerBuildxBuildRead,
+		DeleteContext: resourceDockerBuildxBuildDelete,
+		CustomizeDiff: resourceDockerBuildxBuildCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(dockerBuildxBuildCreateDefaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"context": {
+				Type:        schema.TypeString,
+				Description: "The path to the build context. The path is resolved on the machine running Terraform.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"dockerfile": {
+				Type:        schema.TypeString,
+				Description: "Name of the Dockerfile. If it is not an absolute path, it is resolved relative to `context`. Defaults to `Dockerfile`.",
+				Optional:    true,
+				Default:     "Dockerfile",
+				ForceNew:    true,
+			},
+			"target": {
+				Type:        schema.TypeString,
+				Description: "Set the target build stage to build.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"platforms": {
+				Type:        schema.TypeList,
+				Description: "Set the target platforms for the build, e.g. `linux/amd64`.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"build_args": {
+				Type:        schema.TypeMap,
+				Description: "Pairs for build-time variables in the form of `ENDPOINT : \"https://example.com\"`",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"additional_contexts": {
+				Type:        schema.TypeList,
+				Description: "A list of additional build contexts in the form of `name=path`. Please see https://docs.docker.com/reference/cli/docker/buildx/build/#build-context for more information.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"tags": {
+				Type:        schema.TypeList,
+				Description: "Names of the image in the `name:tag` format. Used by the `registry` and `oci` exporters.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"cache_from": {
+				Type:        schema.TypeList,
+				Description: "External cache sources (e.g., `user/app:cache`, `type=local,src=path/to/dir`).",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"cache_to": {
+				Type:        schema.TypeList,
+				Description: "Cache export destinations (e.g., `user/app:cache`, `type=local,dest=path/to/dir`).",
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"no_cache": {
+				Type:        schema.TypeBool,
+				Description: "Do not use the cache when building.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"pull": {
+				Type:        schema.TypeBool,
+				Description: "Always attempt to pull all referenced images.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"builder": {
+				Type:        schema.TypeString,
+				Description: "The name of the buildx builder to use. If BUILDX_BUILDER environment variable is set, it will be used. If left empty, the provider resolves the builder the same way as the buildx CLI.",
+				Optional:    true,
+				ForceNew:    true,
+				DefaultFunc: schema.EnvDefaultFunc("BUILDX_BUILDER", ""),
+			},
+			"build_log_file": {
+				Type:        schema.TypeString,
+				Description: "Path to a file where the buildx log are written to. If not set, no logs are available.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"output": {
+				Type:        schema.TypeList,
+				Description: "The exporters of the build result. Please see https://docs.docker.com/build/exporters/ for the available attributes of each exporter.",
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:             schema.TypeString,
+							Description:      "The type of the exporter. One of `local`, `tar`, `oci` or `registry`.",
+							Required:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validateStringMatchesPattern(`^(local|tar|oci|registry)$`),
+						},
+						"dest": {
+							Type:        schema.TypeString,
+							Description: "The destination of the exporter. A directory for the `local` exporter and a file for the `tar` and `oci` exporters. Relative paths are resolved relative to the current working directory.",
+							Optional:    true,
+							ForceNew:    true,
+						},
+						"attrs": {
+							Type:        schema.TypeMap,
+							Description: "Additional attributes of the exporter, e.g. `name` and `compression` for the `registry` exporter or `tar = \"false\"` to write an OCI layout directory.",
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"triggers": {
+				Description: "A map of arbitrary strings that, when changed, will force the build to be run again.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"context_hash": {
+				Type:        schema.TypeString,
+				Description: "The sha256 hash of the build context, the Dockerfile and the build args. Files excluded by the `.dockerignore` file are not taken into account. A change of the hash runs the build again.",
+				Computed:    true,
+			},
+			"image_digest": {
+				Type:        schema.TypeString,
+				Description: "The digest of the built image. Only populated by the `oci` and `registry` exporters.",
+				Computed:    true,
+			},
+			"written_files": {
+				Type:        schema.TypeList,
+				Description: "The files in the destinations of the `local`, `tar` and `oci` exporters after the build, including files which already existed in the destinations. If one of them is removed or modified, the build is run again.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:        schema.TypeString,
+							Description: "The absolute path of the file.",
+							Computed:    true,
+						},
+						"sha256": {
+							Type:        schema.TypeString,
+							Description: "The sha256 checksum of the file.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceDockerBuildxBuildCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create Docker client: %w", err))
+	}
+
+	contextHash, err := calculateBuildContextHash(ctx, buildxBuildContextAttributes(d.Get))
+	if err != nil {
+		return diag.Errorf("Unable to calculate build context hash: %s", err)
+	}
+
+	dockerCli, err := createAndInitDockerCli(client)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to create and init Docker CLI: %w", err))
+	}
+
+	options, err := mapBuildxBuildAttributesToBuildOptions(d)
+	if err != nil {
+		return diag.Errorf("Error mapping build attributes: %v", err)
+	}
+	if options.builder == "" {
+		resolvedBuilder, err := ResolveBuilderLikeBuildx(ctx, dockerCli, "")
+		if err != nil {
+			return diag.Errorf("error resolving default builder: %v", err)
+		}
+		options.builder = resolvedBuilder.Name
+	}
+
+	log.Printf("[DEBUG] build options %#v", options)
+
+	result, err := runBuild(ctx, dockerCli, options, d.Get("build_log_file").(string))
+	if err != nil {
+		return diag.Errorf("Error running buildx build: %v", err)
+	}
+
+	writtenFiles, err := collectBuildxWrittenFiles(d.Get("output").([]interface{}))
+	if err != nil {
+		return diag.Errorf("Unable to read the exported files: %v", err)
+	}
+
+	if result.buildRef != "" {
+		d.SetId(result.buildRef)
+	} else {
+		d.SetId(contextHash)
+	}
+	if err := d.Set("context_hash", contextHash); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("image_digest", result.imageDigest); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("written_files", writtenFiles); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDockerBuildxBuildRead(ctx, d, meta)
+}
+
+func resourceDockerBuildxBuildRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	for _, rawFile := range d.Get("written_files").([]interface{}) {
+		writtenFile := rawFile.(map[string]interface{})
+		path := writtenFile["path"].(string)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				log.Printf("[DEBUG] exported file %s does not exist anymore, removing build from state", path)
+				d.SetId("")
+				return nil
+			}
+			return diag.Errorf("Unable to stat exported file %s: %v", path, err)
+		}
+
+		digest, err := digestFileCached(path, info)
+		if err != nil {
+			return diag.Errorf("Unable to hash exported file %s: %v", path, err)
+		}
+		if digest != writtenFile["sha256"].(string) {
+			log.Printf("[DEBUG] exported file %s was modified, removing build from state", path)
+			d.SetId("")
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func resourceDockerBuildxBuildDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// the exported files are kept on purpose, they are the artifacts of the build
+	d.SetId("")
+	return nil
+}
+
+// resourceDockerBuildxBuildCustomizeDiff runs the build again when the hash of the
+// build context changed.
+func resourceDockerBuildxBuildCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	oldContextHash := d.Get("context_hash").(string)
+	if oldContextHash == "" {
+		return nil
+	}
+
+	contextHash, err := calculateBuildContextHash(ctx, buildxBuildContextAttributes(d.Get))
+	if err != nil {
+		// the context might be created during the apply, e.g. by another resource
+		log.Printf("[WARN] unable to calculate build context hash: %v", err)
+		return nil
+	}
+
+	if contextHash == oldContextHash {
+		return nil
+	}
+
+	log.Printf("[DEBUG] build context hash changed from %s to %s, running the build again", oldContextHash, contextHash)
+	if err := d.SetNew("context_hash", contextHash); err != nil {
+		return err
+	}
+	return d.ForceNew("context_hash")
+}
+
+// buildxBuildContextAttributes returns the attributes which are taken into account
+// for the build context hash, in the format of the docker_image build block.
+func buildxBuildContextAttributes(get func(string) interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"context":    get("context"),
+		"dockerfile": get("dockerfile"),
+		"build_args": get("build_args"),
+	}
+}
+
+func mapBuildxBuildAttributesToBuildOptions(d *schema.ResourceData) (buildOptions, error) {
+	options := buildOptions{}
+
+	contextDir, dockerfilePath, _, err := resolveDockerfilePath(d.Get("context").(string), d.Get("dockerfile").(string))
+	if err != nil {
+		return options, fmt.Errorf("error resolving dockerfile path: %w", err)
+	}
+	options.contextPath = contextDir
+	options.dockerfileName = dockerfilePath
+
+	options.target = d.Get("target").(string)
+	options.platforms = interfaceArrayToStringArray(d.Get("platforms").([]interface{}))
+	options.contexts = interfaceArrayToStringArray(d.Get("additional_contexts").([]interface{}))
+	options.tags = interfaceArrayToStringArray(d.Get("tags").([]interface{}))
+	options.cacheFrom = interfaceArrayToStringArray(d.Get("cache_from").([]interface{}))
+	options.cacheTo = interfaceArrayToStringArray(d.Get("cache_to").([]interface{}))
+	options.noCache = d.Get("no_cache").(bool)
+	options.pull = d.Get("pull").(bool)
+	options.builder = d.Get("builder").(string)
+	options.ulimits = dockeropts.NewUlimitOpt(nil)
+
+	for key, value := range d.Get("build_args").(map[string]interface{}) {
+		options.buildArgs = append(options.buildArgs, fmt.Sprintf("%s=%s", key, value.(string)))
+	}
+
+	for _, rawOutput := range d.Get("output").([]interface{}) {
+		output, err := buildxOutputSpec(rawOutput.(map[string]interface{}))
+		if err != nil {
+			return options, err
+		}
+		options.outputs = append(options.outputs, output)
+	}
+
+	return options, nil
+}
+
+// buildxOutputSpec maps an output block to the `--output` format of buildx,
+// e.g. `type=local,dest=/path/to/dist`.
+func buildxOutputSpec(output map[string]interface{}) (string, error) {
+	outputType := output["type"].(string)
+	parts := []string{"type=" + outputType}
+
+	dest, _ := output["dest"].(string)
+	switch outputType {
+	case "local", "tar", "oci":
+		if dest == "" {
+			return "", fmt.Errorf("the %s exporter requires dest to be set", outputType)
+		}
+	}
+	if dest != "" {
+		if dest == "-" {
+			return "", fmt.Errorf("exporting to stdout is not supported")
+		}
+		absDest, err := filepath.Abs(dest)
+		if err != nil {
+			return "", fmt.Errorf("unable to resolve dest %s: %w", dest, err)
+		}
+		parts = append(parts, "dest="+absDest)
+	}
+
+	attrs, _ := output["attrs"].(map[string]interface{})
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, attrs[key]))
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+// collectBuildxWrittenFiles returns the path and sha256 checksum of every file
+// in the destinations of the local, tar and oci exporters after the build.
+func collectBuildxWrittenFiles(outputs []interface{}) ([]interface{}, error) {
+	writtenFiles := make([]interface{}, 0)
+	err := walkBuildxOutputFiles(outputs, func(path string, info fs.FileInfo) error {
+		digest, err := digestFileCached(path, info)
+		if err != nil {
+			return err
+		}
+		writtenFiles = append(writtenFiles, map[string]interface{}{
+			"path":   path,
+			"sha256": digest,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return writtenFiles, nil
+}
+
+// walkBuildxOutputFiles calls fn for every regular file in the destinations of the
+// local, tar and oci exporters. Destinations which do not exist are skipped.
+func walkBuildxOutputFiles(outputs []interface{}, fn func(path string, info fs.FileInfo) error) error {
+	for _, rawOutput := range outputs {
+		output := rawOutput.(map[string]interface{})
+		dest, _ := output["dest"].(string)
+		if output["type"].(string) == "registry" || dest == "" {
+			continue
+		}
+
+		absDest, err := filepath.Abs(dest)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(absDest); os.IsNotExist(err) {
+			continue
+		}
+
+		err = filepath.WalkDir(absDest, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			return fn(path, info)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDockerBuildxBuild_LocalOutput(t *testing.T) {
+	contextDir := t.TempDir()
+	destDir := filepath.Join(t.TempDir(), "dist")
+
+	dockerfile := "FROM scratch AS artifact\nCOPY hello.txt /hello.txt\n"
+	if err := os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+		t.Fatalf("Failed to create Dockerfile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(contextDir, "hello.txt"), []byte("hello world\n"), 0644); err != nil {
+		t.Fatalf("Failed to create hello.txt: %v", err)
+	}
+
+	config := loadTestConfiguration(t, RESOURCE, "docker_buildx_build", "testAccDockerBuildxBuildLocalOutput")
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, contextDir, destDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_buildx_build.foo", "written_files.#", "1"),
+					resource.TestCheckResourceAttr("docker_buildx_build.foo", "written_files.0.path", filepath.Join(destDir, "hello.txt")),
+					resource.TestCheckResourceAttr("docker_buildx_build.foo", "written_files.0.sha256", "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447"),
+					resource.TestMatchResourceAttr("docker_buildx_build.foo", "context_hash", regexp.MustCompile(`\A[a-f0-9]{64}\z`)),
+				),
+			},
+			{
+				// a changed build context runs the build again
+				PreConfig: func() {
+					if err := os.WriteFile(filepath.Join(contextDir, "hello.txt"), []byte("hello terraform\n"), 0644); err != nil {
+						t.Fatalf("Failed to update hello.txt: %v", err)
+					}
+				},
+				Config:             fmt.Sprintf(config, contextDir, destDir),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestBuildxOutputSpec(t *testing.T) {
+	absDest, err := filepath.Abs("dist")
+	if err != nil {
+		t.Fatalf("Failed to resolve dist: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		output   map[string]interface{}
+		expected string
+		err      bool
+	}{
+		{
+			name:     "local",
+			output:   map[string]interface{}{"type": "local", "dest": "dist"},
+			expected: "type=local,dest=" + absDest,
+		},
+		{
+			name: "registry with attrs",
+			output: map[string]interface{}{
+				"type":  "registry",
+				"attrs": map[string]interface{}{"name": "registry.example.com/app:latest", "compression": "zstd"},
+			},
+			expected: "type=registry,compression=zstd,name=registry.example.com/app:latest",
+		},
+		{
+			name:   "tar without dest",
+			output: map[string]interface{}{"type": "tar"},
+			err:    true,
+		},
+		{
+			name:   "stdout",
+			output: map[string]interface{}{"type": "oci", "dest": "-"},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildxOutputSpec(tt.output)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("Expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCollectBuildxWrittenFiles(t *testing.T) {
+	destDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(destDir, "bin"), 0755); err != nil {
+		t.Fatalf("Failed to create bin dir: %v", err)
+	}
+
+	outputs := []interface{}{
+		map[string]interface{}{"type": "local", "dest": destDir},
+		map[string]interface{}{"type": "registry", "dest": ""},
+		map[string]interface{}{"type": "tar", "dest": filepath.Join(destDir, "missing", "out.tar")},
+	}
+	if err := os.WriteFile(filepath.Join(destDir, "README.md"), []byte("existing\n"), 0644); err != nil {
+		t.Fatalf("Failed to create README.md: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(destDir, "bin", "app"), []byte("hello world\n"), 0755); err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	writtenFiles, err := collectBuildxWrittenFiles(outputs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(writtenFiles) != 2 {
+		t.Fatalf("Expected 2 written files, got %v", writtenFiles)
+	}
+	if writtenFiles[0].(map[string]interface{})["path"] != filepath.Join(destDir, "README.md") {
+		t.Errorf("Expected the existing README.md to be recorded, got %v", writtenFiles[0])
+	}
+	writtenFile := writtenFiles[1].(map[string]interface{})
+	if writtenFile["path"] != filepath.Join(destDir, "bin", "app") {
+		t.Errorf("Unexpected path %v", writtenFile["path"])
+	}
+	if writtenFile["sha256"] != "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447" {
+		t.Errorf("Unexpected sha256 %v", writtenFile["sha256"])
+	}
+}
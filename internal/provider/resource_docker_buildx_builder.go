@@ -1,17 +1,27 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/docker/buildx/builder"
+	"github.com/docker/buildx/store"
 	"github.com/docker/buildx/store/storeutil"
+	"github.com/docker/buildx/util/confutil"
+	"github.com/docker/buildx/util/dockerutil"
+	"github.com/docker/buildx/util/platformutil"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/flags"
+	"github.com/google/shlex"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 
@@ -28,7 +38,12 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceDockerBuildxBuilderCreate,
 		ReadContext:   resourceDockerBuildxBuilderRead,
+		UpdateContext: resourceDockerBuildxBuilderUpdate,
 		DeleteContext: resourceDockerBuildxBuilderDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceDockerBuildxBuilderCustomizeDiff,
 		Description:   "Manages a Docker Buildx builder instance. This resource allows you to create a  buildx builder with various configurations such as driver, nodes, and platform settings. Please see https://github.com/docker/buildx/blob/master/docs/reference/buildx_create.md for more documentation",
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -45,6 +60,10 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 				Description:   "The driver to use for the Buildx builder (e.g., docker-container, kubernetes).",
 				ConflictsWith: []string{"docker_container", "kubernetes", "remote"},
 				ForceNew:      true,
+				DiffSuppressFunc: func(k, oldV, newV string, d *schema.ResourceData) bool {
+					// the driver is defined by the driver configuration blocks if one is set
+					return buildxBuilderDriverBlockSet(d.Get)
+				},
 			},
 			"driver_options": {
 				Type:        schema.TypeMap,
@@ -54,14 +73,12 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 					Type: schema.TypeString,
 				},
 				ConflictsWith: []string{"docker_container", "kubernetes", "remote"},
-				ForceNew:      true,
 			},
 			"node": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Create/modify node with given name",
-				Default:     "",
-				ForceNew:    true,
+				Computed:    true,
+				Description: "Create/modify node with given name. If not specified, the name of the node created by buildx is used. Renaming the node replaces it within the builder.",
 			},
 			"platform": {
 				Type:        schema.TypeList,
@@ -70,28 +87,24 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				ForceNew: true,
 			},
 			"buildkit_flags": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "BuildKit flags to set for the builder.",
 				Default:     "",
-				ForceNew:    true,
 			},
 			"buildkit_config": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "BuildKit daemon config file",
 				Default:     "",
-				ForceNew:    true,
 			},
 			"use": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
-				Description: "Set the current builder instance as the default for the current context.",
-				ForceNew:    true,
+				Description: "Set the current builder instance as the default for the current context. Changing it to `false` switches the context back to the default builder if the builder is still the current one.",
 			},
 			"append": {
 				Type:        schema.TypeBool,
@@ -105,14 +118,12 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 				Description: "Automatically boot the builder after creation. Defaults to `false`",
-				ForceNew:    true,
 			},
 			"endpoint": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "The endpoint or context to use for the Buildx builder, where context is the name of a context from docker context ls and endpoint is the address for Docker socket (eg. DOCKER_HOST value). By default, the current Docker configuration is used for determining the context/endpoint value.",
 				Default:     "",
-				ForceNew:    true,
 			},
 			"kubernetes": {
 				Type:          schema.TypeList,
@@ -120,7 +131,6 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 				MaxItems:      1,
 				Description:   "Configuration block for the Kubernetes driver.",
 				ConflictsWith: []string{"docker_container", "remote", "driver", "driver_options"},
-				ForceNew:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"image": {
@@ -271,7 +281,6 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 			"docker_container": {
 				Type:          schema.TypeList,
 				Optional:      true,
-				ForceNew:      true,
 				MaxItems:      1,
 				Description:   "Configuration block for the Docker-Container driver.",
 				ConflictsWith: []string{"kubernetes", "remote", "driver", "driver_options"},
@@ -354,7 +363,6 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 			"remote": {
 				Type:          schema.TypeList,
 				Optional:      true,
-				ForceNew:      true,
 				MaxItems:      1,
 				Description:   "Configuration block for the Remote driver.",
 				ConflictsWith: []string{"kubernetes", "docker_container", "driver", "driver_options"},
@@ -389,46 +397,73 @@ func resourceDockerBuildxBuilder() *schema.Resource {
 					},
 				},
 			},
+			"nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The nodes of the builder as reported by `docker buildx inspect`.",
+				Elem:        buildxBuilderNodeSchema(),
+			},
 		},
 	}
 
 }
 
+// buildxBuilderNodeSchema is the schema of a node of a builder, see flattenBuildxBuilderNodes.
+func buildxBuilderNodeSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the node.",
+			},
+			"endpoint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The endpoint of the node.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the node, e.g. `running`, `stopped` or `inactive`. Empty if the node could not be reached.",
+			},
+			"error": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The error returned when the node could not be reached.",
+			},
+			"buildkit_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of BuildKit running on the node. Empty if the node is not running.",
+			},
+			"buildkit_flags": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The flags BuildKit is started with on the node.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"platforms": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The platforms supported by the node. Platforms fixed with `platform` come first and are marked with `*`.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
 // resourceDockerBuildxBuilderCreate handles the creation of a Buildx builder
 func resourceDockerBuildxBuilderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	name := d.Get("name").(string)
-	driver := d.Get("driver").(string)
+	driver, driverOptions := buildxBuilderDriverAndOptions(d.Get)
 	platform := d.Get("platform").([]interface{})
-	driverOptions := make([]string, 0)
-	v, ok := d.GetOk("driver_options")
-	if ok {
-		driverOptions = processDriverOptions(v.(map[string]interface{}))
-	}
 	appendAction := d.Get("append").(bool)
 	use := d.Get("use").(bool)
 
 	log.Printf("[DEBUG] Creating Buildx builder: %s", name)
 
-	if kubernetesConfig, ok := d.GetOk("kubernetes"); ok {
-		driver = "kubernetes"
-		kubernetes := kubernetesConfig.([]interface{})[0].(map[string]interface{})
-		driverOptions = processDriverOptions(kubernetes)
-	}
-
-	if dockerContainerConfig, ok := d.GetOk("docker_container"); ok {
-		driver = "docker-container"
-		dockerContainer := dockerContainerConfig.([]interface{})[0].(map[string]interface{})
-		driverOptions = processDriverOptions(dockerContainer)
-	}
-
-	// Updated the Create function to handle all Remote driver-specific parameters
-	if remoteConfig, ok := d.GetOk("remote"); ok {
-		driver = "remote"
-		remote := remoteConfig.([]interface{})[0].(map[string]interface{})
-		driverOptions = processDriverOptions(remote)
-	}
-
 	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to create Docker client: %w", err))
@@ -458,22 +493,17 @@ func resourceDockerBuildxBuilderCreate(ctx context.Context, d *schema.ResourceDa
 	log.Printf("[DEBUG] Driver: %s", driver)
 	log.Printf("[DEBUG] Driver options: %s", driverOptions)
 
-	var ep string
-	v = d.Get("endpoint").(string)
-	if v != "" {
-		ep = v.(string)
-	}
-
+	nodeName := d.Get("node").(string)
 	b, err := builder.Create(ctx, txn, t, builder.CreateOpts{
 		Name:                name,
 		Driver:              driver,
-		NodeName:            d.Get("node").(string),
+		NodeName:            nodeName,
 		Platforms:           stringListToStringSlice(platform),
 		DriverOpts:          driverOptions,
 		BuildkitdFlags:      d.Get("buildkit_flags").(string),
 		BuildkitdConfigFile: d.Get("buildkit_config").(string),
 		Use:                 use,
-		Endpoint:            ep,
+		Endpoint:            d.Get("endpoint").(string),
 		Append:              appendAction,
 	})
 
@@ -491,12 +521,89 @@ func resourceDockerBuildxBuilderCreate(ctx context.Context, d *schema.ResourceDa
 		}
 	}
 
+	// buildx generates a name for the node if none is given, which is either the
+	// only node of a new builder or the one appended last
+	if nodeName == "" && len(b.NodeGroup.Nodes) > 0 {
+		nodeName = b.NodeGroup.Nodes[len(b.NodeGroup.Nodes)-1].Name
+	}
+
 	d.SetId(b.Name)
 	d.Set("name", b.Name)
+	d.Set("node", nodeName)
 
 	return resourceDockerBuildxBuilderRead(ctx, d, meta)
 }
 
+// buildxBuilderDriverAndOptions returns the driver of the builder and its options. A driver
+// configuration block takes precedence over `driver` and `driver_options`.
+func buildxBuilderDriverAndOptions(get func(string) interface{}) (string, []string) {
+	driver := get("driver").(string)
+	driverOptions := processDriverOptions(get("driver_options").(map[string]interface{}))
+
+	for _, block := range buildxBuilderDriverBlocks {
+		config := get(block.key).([]interface{})
+		if len(config) == 0 {
+			continue
+		}
+		driver = block.driver
+		driverOptions = make([]string, 0)
+		if options, ok := config[0].(map[string]interface{}); ok {
+			driverOptions = processDriverOptions(options)
+		}
+	}
+	return driver, driverOptions
+}
+
+// buildxBuilderDriverBlockSet returns whether one of the driver configuration blocks is set.
+func buildxBuilderDriverBlockSet(get func(string) interface{}) bool {
+	for _, block := range buildxBuilderDriverBlocks {
+		if len(get(block.key).([]interface{})) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var buildxBuilderDriverBlocks = []struct {
+	key    string
+	driver string
+}{
+	{key: "kubernetes", driver: "kubernetes"},
+	{key: "docker_container", driver: "docker-container"},
+	{key: "remote", driver: "remote"},
+}
+
+// resourceDockerBuildxBuilderCustomizeDiff replaces the builder if its driver changes, as
+// buildx cannot change the driver of an existing builder.
+func resourceDockerBuildxBuilderCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	oldDriver, _ := buildxBuilderDriverAndOptions(func(key string) interface{} {
+		o, _ := d.GetChange(key)
+		return o
+	})
+	newDriver, _ := buildxBuilderDriverAndOptions(func(key string) interface{} {
+		_, n := d.GetChange(key)
+		return n
+	})
+	if oldDriver == newDriver {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Driver of Buildx builder %s changes from %s to %s, replacing it", d.Id(), oldDriver, newDriver)
+	for _, key := range []string{"driver", "kubernetes", "docker_container", "remote"} {
+		if !d.HasChange(key) {
+			continue
+		}
+		if err := d.ForceNew(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func processDriverOptions(driverOptionsMap map[string]interface{}) []string {
 	// Iterate over the driver options and append them to a string list
 	resultStringList := make([]string, 0)
@@ -523,25 +630,16 @@ func processDriverOptions(driverOptionsMap map[string]interface{}) []string {
 // resourceDockerBuildxBuilderRead handles reading the state of a Buildx builder
 // corresponding file in buildx repo: https://github.com/docker/buildx/blob/master/commands/inspect.go
 func resourceDockerBuildxBuilderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
-	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to create Docker client: %w", err))
-	}
-
-	dockerCli, error := command.NewDockerCli()
-	if error != nil {
-		return diag.FromErr(fmt.Errorf("failed to create Docker CLI: %w", error))
-	}
-	err = dockerCli.Initialize(&flags.ClientOptions{Hosts: []string{client.DaemonHost()}})
+	dockerCli, err := newBuildxBuilderDockerCli(ctx, d, meta)
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to initialize Docker CLI: %w", err))
+		return diag.FromErr(err)
 	}
 	name := d.Id()
 
 	log.Printf("[DEBUG] Reading Buildx builder: %s", name)
 
-	_, err = builder.New(dockerCli,
-		builder.WithName(d.Get("name").(string)),
+	b, err := builder.New(dockerCli,
+		builder.WithName(name),
 		builder.WithSkippedValidation(),
 	)
 	if err != nil {
@@ -554,9 +652,373 @@ func resourceDockerBuildxBuilderRead(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(err)
 	}
 
+	// the data of the nodes is loaded from the drivers, do not wait forever for unreachable ones
+	loadCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+	nodes, err := b.LoadNodes(loadCtx, builder.WithData())
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to load the nodes of Buildx builder %s: %w", name, err))
+	}
+
+	nodeName := d.Get("node").(string)
+	if nodeName == "" && len(nodes) > 0 {
+		// imported or created by an older version of the provider
+		nodeName = nodes[0].Name
+	}
+	var managedNode *builder.Node
+	for i := range nodes {
+		if nodes[i].Name == nodeName {
+			managedNode = &nodes[i]
+		}
+	}
+	if managedNode == nil {
+		log.Printf("[DEBUG] Node %s of Buildx builder %s not found, removing from state", nodeName, name)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", b.Name)
+	d.Set("driver", b.Driver)
+	d.Set("node", nodeName)
+	if buildxBuilderPlatformsDrifted(d.Get("platform").([]interface{}), managedNode.Node.Platforms) {
+		d.Set("platform", platformutil.Format(managedNode.Node.Platforms))
+	}
+	// buildx resolves an empty endpoint to the one of the current Docker context
+	if endpoint := d.Get("endpoint").(string); endpoint != "" && endpoint != managedNode.Node.Endpoint {
+		d.Set("endpoint", managedNode.Node.Endpoint)
+	}
+	if buildxBuilderFlagsDrifted(d.Get("buildkit_flags").(string), managedNode.Node.BuildkitdFlags) {
+		d.Set("buildkit_flags", strings.Join(managedNode.Node.BuildkitdFlags, " "))
+	}
+	if !buildxBuilderDriverBlockSet(d.Get) && buildxBuilderDriverOptionsDrifted(d.Get("driver_options").(map[string]interface{}), managedNode.Node.DriverOpts) {
+		d.Set("driver_options", managedNode.Node.DriverOpts)
+	}
+	// only the content of the config file is stored, so a changed content is shown as
+	// a removed config to apply it again
+	if config := d.Get("buildkit_config").(string); config != "" && buildxBuilderConfigDrifted(config, managedNode.Node.Files) {
+		log.Printf("[DEBUG] BuildKit config of node %s of Buildx builder %s differs from %s", nodeName, name, config)
+		d.Set("buildkit_config", "")
+	}
+	d.Set("nodes", flattenBuildxBuilderNodes(nodes))
+
+	current, err := currentBuildxBuilder(dockerCli)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("use", current == b.Name)
+
+	return nil
+}
+
+// currentBuildxBuilder returns the name of the builder which is used for the current Docker
+// context, or an empty string if it is the default builder.
+func currentBuildxBuilder(dockerCli command.Cli) (string, error) {
+	txn, release, err := storeutil.GetStore(dockerCli)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	ep, err := dockerutil.GetCurrentEndpoint(dockerCli)
+	if err != nil {
+		return "", err
+	}
+	ng, err := txn.Current(ep)
+	if err != nil || ng == nil {
+		return "", err
+	}
+	return ng.Name, nil
+}
+
+// buildxBuilderFlagsDrifted returns whether the BuildKit flags of a node differ from the
+// configured ones. buildx adds the network.host entitlement if no entitlement is set.
+func buildxBuilderFlagsDrifted(configured string, current []string) bool {
+	var expected []string
+	if configured != "" {
+		var err error
+		if expected, err = shlex.Split(configured); err != nil {
+			return true
+		}
+	}
+	if slices.Equal(expected, current) {
+		return false
+	}
+	return !slices.Equal(append(expected, "--allow-insecure-entitlement=network.host"), current)
+}
+
+// buildxBuilderDriverOptionsDrifted returns whether the driver options of a node differ from
+// the configured ones, after they are processed in the same way as for the create.
+func buildxBuilderDriverOptionsDrifted(configured map[string]interface{}, current map[string]string) bool {
+	expected := make(map[string]string)
+	for _, option := range processDriverOptions(configured) {
+		key, value, _ := strings.Cut(option, "=")
+		expected[key] = value
+	}
+	if len(current) == 0 {
+		return len(expected) > 0
+	}
+	return !maps.Equal(expected, current)
+}
+
+// buildxBuilderConfigDrifted returns whether the BuildKit config file or one of the files it
+// references differs from the content stored for the node.
+func buildxBuilderConfigDrifted(configured string, current map[string][]byte) bool {
+	expected, err := confutil.LoadConfigFiles(configured)
+	if err != nil {
+		return true
+	}
+	return !maps.EqualFunc(expected, current, bytes.Equal)
+}
+
+// resourceDockerBuildxBuilderUpdate changes the node of the builder managed by the resource
+// through the buildx store, the same way as `docker buildx create --append --node` does. The
+// node is restarted if needed, but keeps its state and with that the build cache.
+func resourceDockerBuildxBuilderUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Id()
+	driver, driverOptions := buildxBuilderDriverAndOptions(d.Get)
+	platform := stringListToStringSlice(d.Get("platform").([]interface{}))
+
+	dockerCli, err := newBuildxBuilderDockerCli(ctx, d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	txn, release, err := storeutil.GetStore(dockerCli)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Ensure the file lock gets released no matter what happens.
+	defer release()
+
+	b, err := builder.New(dockerCli,
+		builder.WithName(name),
+		builder.WithStore(txn),
+		builder.WithSkippedValidation(),
+	)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	nodes, err := b.LoadNodes(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	o, n := d.GetChange("node")
+	oldNodeName, nodeName := o.(string), n.(string)
+	if nodeName == "" {
+		nodeName = oldNodeName
+	}
+	nodeRenamed := oldNodeName != "" && oldNodeName != nodeName
+	needsRestart := nodeRenamed || d.HasChanges("driver_options", "buildkit_flags", "buildkit_config", "endpoint", "kubernetes", "docker_container", "remote")
+
+	if needsRestart {
+		// a renamed node gets a new container or deployment, so the state of the old one is removed
+		for _, node := range nodes {
+			if node.Name != oldNodeName {
+				continue
+			}
+			log.Printf("[DEBUG] Stopping node %s of Buildx builder %s", node.Name, name)
+			if err := rm(ctx, []builder.Node{node}, rmOptions{keepState: !nodeRenamed}); err != nil {
+				return diag.FromErr(fmt.Errorf("failed to stop node %s of Buildx builder %s: %w", node.Name, name, err))
+			}
+		}
+	}
+
+	if nodeRenamed {
+		log.Printf("[DEBUG] Removing node %s from Buildx builder %s", oldNodeName, name)
+		if err := removeBuildxBuilderNode(ctx, txn, dockerCli, name, oldNodeName); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to remove node %s from Buildx builder %s: %w", oldNodeName, name, err))
+		}
+	}
+
+	// buildx merges the files of the config into the ones stored for the node, so they
+	// are replaced here to drop the files which are not part of the config anymore
+	if err := resetBuildxBuilderNodeFiles(txn, name, nodeName, d.Get("buildkit_config").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Updating node %s of Buildx builder %s", nodeName, name)
+	_, err = builder.Create(ctx, txn, dockerCli, builder.CreateOpts{
+		Name:                name,
+		Driver:              driver,
+		NodeName:            nodeName,
+		Platforms:           platform,
+		DriverOpts:          driverOptions,
+		BuildkitdFlags:      d.Get("buildkit_flags").(string),
+		BuildkitdConfigFile: d.Get("buildkit_config").(string),
+		Use:                 d.Get("use").(bool),
+		Endpoint:            d.Get("endpoint").(string),
+		Append:              true,
+	})
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to update Buildx builder: %w", err))
+	}
+
+	// buildx only changes the settings of an existing node which are given, so
+	// settings which were removed from the configuration are cleared here
+	if len(platform) == 0 || len(driverOptions) == 0 {
+		ng, err := txn.NodeGroupByName(name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for i := range ng.Nodes {
+			if ng.Nodes[i].Name != nodeName {
+				continue
+			}
+			if len(platform) == 0 {
+				ng.Nodes[i].Platforms = nil
+			}
+			if len(driverOptions) == 0 {
+				ng.Nodes[i].DriverOpts = nil
+			}
+		}
+		if err := txn.Save(ng); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("use") && !d.Get("use").(bool) {
+		if err := unuseBuildxBuilder(txn, dockerCli, name); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to unset Buildx builder %s as the current builder: %w", name, err))
+		}
+	}
+
+	// The store is no longer used from this point.
+	// Release it so we aren't holding the file lock during the boot.
+	release()
+
+	if d.Get("bootstrap").(bool) {
+		b, err := builder.New(dockerCli, builder.WithName(name))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if _, err = b.Boot(ctx); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to bootstrap Buildx builder: %w", err))
+		}
+	}
+
+	d.Set("node", nodeName)
+
+	return resourceDockerBuildxBuilderRead(ctx, d, meta)
+}
+
+// resetBuildxBuilderNodeFiles replaces the files stored for a node of the builder with the
+// ones of the BuildKit config file. Nodes which do not exist yet are skipped.
+func resetBuildxBuilderNodeFiles(txn *store.Txn, name, nodeName, config string) error {
+	ng, err := txn.NodeGroupByName(name)
+	if err != nil {
+		return err
+	}
+
+	var files map[string][]byte
+	if config != "" {
+		if files, err = confutil.LoadConfigFiles(config); err != nil {
+			return err
+		}
+	}
+	for i := range ng.Nodes {
+		if ng.Nodes[i].Name == nodeName {
+			ng.Nodes[i].Files = files
+			return txn.Save(ng)
+		}
+	}
 	return nil
 }
 
+// unuseBuildxBuilder switches the current Docker context back to the default builder if the
+// builder is the current one, the same way as `docker buildx use default` does.
+func unuseBuildxBuilder(txn *store.Txn, dockerCli command.Cli, name string) error {
+	ep, err := dockerutil.GetCurrentEndpoint(dockerCli)
+	if err != nil {
+		return err
+	}
+	ng, err := txn.Current(ep)
+	if err != nil {
+		return err
+	}
+	if ng == nil || ng.Name != name {
+		return nil
+	}
+	log.Printf("[DEBUG] Unsetting Buildx builder %s as the current builder", name)
+	return txn.SetCurrent(ep, "", false, false)
+}
+
+// removeBuildxBuilderNode removes a node from a builder, the same way as `docker buildx create --leave` does.
+func removeBuildxBuilderNode(ctx context.Context, txn *store.Txn, dockerCli command.Cli, name, nodeName string) error {
+	ng, err := txn.NodeGroupByName(name)
+	if err != nil {
+		return err
+	}
+	if len(ng.Nodes) > 1 {
+		return builder.Leave(ctx, txn, dockerCli, builder.LeaveOpts{Name: name, NodeName: nodeName})
+	}
+
+	// buildx refuses to remove the last node of a builder, but it is replaced right after
+	ng.Nodes = slices.DeleteFunc(ng.Nodes, func(node store.Node) bool {
+		return node.Name == nodeName
+	})
+	return txn.Save(ng)
+}
+
+// newBuildxBuilderDockerCli returns a Docker CLI for the daemon of the provider, which is used
+// to access the buildx store.
+func newBuildxBuilderDockerCli(ctx context.Context, d *schema.ResourceData, meta interface{}) (*command.DockerCli, error) {
+	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+
+	dockerCli, err := command.NewDockerCli()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker CLI: %w", err)
+	}
+	err = dockerCli.Initialize(&flags.ClientOptions{Hosts: []string{client.DaemonHost()}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Docker CLI: %w", err)
+	}
+	return dockerCli, nil
+}
+
+// buildxBuilderPlatformsDrifted returns whether the fixed platforms of a node differ from
+// the configured ones. Both are compared in their normalized form.
+func buildxBuilderPlatformsDrifted(configured []interface{}, current []ocispecs.Platform) bool {
+	parsed, err := platformutil.Parse(stringListToStringSlice(configured))
+	if err != nil {
+		return true
+	}
+	return !slices.Equal(platformutil.Format(parsed), platformutil.Format(platformutil.Dedupe(current)))
+}
+
+func flattenBuildxBuilderNodes(nodes []builder.Node) []interface{} {
+	flattened := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		status := ""
+		errorMessage := ""
+		if node.Err != nil {
+			errorMessage = node.Err.Error()
+		} else if node.DriverInfo != nil {
+			status = node.DriverInfo.Status.String()
+		}
+
+		buildkitFlags := node.BuildkitdFlags
+		if buildkitFlags == nil {
+			buildkitFlags = []string{}
+		}
+		platforms := platformutil.FormatInGroups(node.Node.Platforms, node.Platforms)
+
+		flattened = append(flattened, map[string]interface{}{
+			"name":             node.Name,
+			"endpoint":         node.Endpoint,
+			"status":           status,
+			"error":            errorMessage,
+			"buildkit_version": node.Version,
+			"buildkit_flags":   buildkitFlags,
+			"platforms":        platforms,
+		})
+	}
+	return flattened
+}
+
 // resourceDockerBuildxBuilderDelete handles the deletion of a Buildx builder
 // corresponding file in buildx repo: https://github.com/docker/buildx/blob/master/commands/rm.go
 func resourceDockerBuildxBuilderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -1,10 +1,21 @@
 package provider
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/docker/buildx/builder"
+	"github.com/docker/buildx/driver"
+	"github.com/docker/buildx/store"
+	"github.com/docker/buildx/util/confutil"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 func TestAccDockerBuildxBuilder_DockerContainerDriver(t *testing.T) {
@@ -51,3 +62,276 @@ func TestAccDockerBuildxBuilder_OutOfBandDeletion(t *testing.T) {
 		},
 	})
 }
+
+func TestAccDockerBuildxBuilder_Update(t *testing.T) {
+	var builderID string
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_buildx_builder", "testAccDockerBuildxBuilderUpdate"), "node0", `"linux/amd64"`, "--debug"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "node", "node0"),
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "nodes.#", "1"),
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "nodes.0.status", "running"),
+					resource.TestCheckResourceAttrSet("docker_buildx_builder.foo", "nodes.0.buildkit_version"),
+					resource.TestCheckTypeSetElemAttr("docker_buildx_builder.foo", "nodes.0.buildkit_flags.*", "--debug"),
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "nodes.0.platforms.0", "linux/amd64*"),
+					testCheckResourceID("docker_buildx_builder.foo", &builderID),
+				),
+			},
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_buildx_builder", "testAccDockerBuildxBuilderUpdate"), "node0", `"linux/amd64", "linux/arm64"`, "--debug --trace"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "nodes.0.status", "running"),
+					resource.TestCheckTypeSetElemAttr("docker_buildx_builder.foo", "nodes.0.buildkit_flags.*", "--trace"),
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "nodes.0.platforms.1", "linux/arm64*"),
+					testCheckResourceIDUnchanged("docker_buildx_builder.foo", &builderID),
+				),
+			},
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_buildx_builder", "testAccDockerBuildxBuilderUpdate"), "node1", `"linux/amd64"`, "--debug"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "node", "node1"),
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "nodes.#", "1"),
+					resource.TestCheckResourceAttr("docker_buildx_builder.foo", "nodes.0.name", "node1"),
+					testCheckResourceIDUnchanged("docker_buildx_builder.foo", &builderID),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDockerBuildxBuilder_Import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: loadTestConfiguration(t, RESOURCE, "docker_buildx_builder", "testAccDockerBuildxBuilderDockerContainer"),
+			},
+			{
+				ResourceName:      "docker_buildx_builder.foo",
+				ImportState:       true,
+				ImportStateId:     "foo",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"append",
+					"bootstrap",
+					"buildkit_config",
+					"buildkit_flags",
+					"docker_container",
+					"driver_options",
+					"endpoint",
+					"use",
+				},
+			},
+		},
+	})
+}
+
+func TestBuildxBuilderDriverAndOptions(t *testing.T) {
+	config := map[string]interface{}{
+		"driver":           "docker-container",
+		"driver_options":   map[string]interface{}{"network": "host"},
+		"kubernetes":       []interface{}{},
+		"docker_container": []interface{}{},
+		"remote":           []interface{}{},
+	}
+	get := func(key string) interface{} { return config[key] }
+
+	driver, driverOptions := buildxBuilderDriverAndOptions(get)
+	if driver != "docker-container" || !reflect.DeepEqual(driverOptions, []string{"network=host"}) {
+		t.Errorf("unexpected driver %q with options %v", driver, driverOptions)
+	}
+	if buildxBuilderDriverBlockSet(get) {
+		t.Error("expected no driver block to be set")
+	}
+
+	config["remote"] = []interface{}{map[string]interface{}{"servername": "buildkitd", "default_load": false}}
+	driver, driverOptions = buildxBuilderDriverAndOptions(get)
+	if driver != "remote" || !reflect.DeepEqual(driverOptions, []string{"servername=buildkitd"}) {
+		t.Errorf("unexpected driver %q with options %v", driver, driverOptions)
+	}
+	if !buildxBuilderDriverBlockSet(get) {
+		t.Error("expected a driver block to be set")
+	}
+
+	// an empty block selects the driver without options
+	config["remote"] = []interface{}{nil}
+	driver, driverOptions = buildxBuilderDriverAndOptions(get)
+	if driver != "remote" || len(driverOptions) != 0 {
+		t.Errorf("unexpected driver %q with options %v", driver, driverOptions)
+	}
+}
+
+func TestBuildxBuilderPlatformsDrifted(t *testing.T) {
+	amd64 := ocispecs.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := ocispecs.Platform{OS: "linux", Architecture: "arm64"}
+
+	cases := []struct {
+		name       string
+		configured []interface{}
+		current    []ocispecs.Platform
+		drifted    bool
+	}{
+		{name: "none", configured: []interface{}{}, current: nil, drifted: false},
+		{name: "equal", configured: []interface{}{"linux/amd64", "linux/arm64"}, current: []ocispecs.Platform{amd64, arm64}, drifted: false},
+		{name: "normalized", configured: []interface{}{"linux/x86_64"}, current: []ocispecs.Platform{amd64}, drifted: false},
+		{name: "added", configured: []interface{}{"linux/amd64"}, current: []ocispecs.Platform{amd64, arm64}, drifted: true},
+		{name: "removed", configured: []interface{}{"linux/amd64"}, current: nil, drifted: true},
+		{name: "invalid", configured: []interface{}{"linux/amd64/v1/x"}, current: []ocispecs.Platform{amd64}, drifted: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if drifted := buildxBuilderPlatformsDrifted(c.configured, c.current); drifted != c.drifted {
+				t.Errorf("expected drifted to be %t, got %t", c.drifted, drifted)
+			}
+		})
+	}
+}
+
+func TestBuildxBuilderFlagsDrifted(t *testing.T) {
+	cases := []struct {
+		name       string
+		configured string
+		current    []string
+		drifted    bool
+	}{
+		{name: "none", configured: "", current: nil, drifted: false},
+		{name: "default entitlement", configured: "", current: []string{"--allow-insecure-entitlement=network.host"}, drifted: false},
+		{name: "equal", configured: "--debug --trace", current: []string{"--debug", "--trace"}, drifted: false},
+		{name: "quoted", configured: `--oci-worker-gc-keepstorage "10 GB"`, current: []string{"--oci-worker-gc-keepstorage", "10 GB"}, drifted: false},
+		{name: "entitlement added", configured: "--debug", current: []string{"--debug", "--allow-insecure-entitlement=network.host"}, drifted: false},
+		{name: "changed", configured: "--debug", current: []string{"--trace"}, drifted: true},
+		{name: "removed", configured: "--debug", current: nil, drifted: true},
+		{name: "invalid", configured: `--debug "`, current: []string{"--debug"}, drifted: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if drifted := buildxBuilderFlagsDrifted(c.configured, c.current); drifted != c.drifted {
+				t.Errorf("expected drifted to be %t, got %t", c.drifted, drifted)
+			}
+		})
+	}
+}
+
+func TestBuildxBuilderDriverOptionsDrifted(t *testing.T) {
+	cases := []struct {
+		name       string
+		configured map[string]interface{}
+		current    map[string]string
+		drifted    bool
+	}{
+		{name: "none", configured: map[string]interface{}{}, current: nil, drifted: false},
+		{name: "equal", configured: map[string]interface{}{"image": "moby/buildkit:latest", "network": "host"}, current: map[string]string{"image": "moby/buildkit:latest", "network": "host"}, drifted: false},
+		{name: "underscores", configured: map[string]interface{}{"default_load": "true"}, current: map[string]string{"default-load": "true"}, drifted: false},
+		{name: "empty value", configured: map[string]interface{}{"image": ""}, current: nil, drifted: false},
+		{name: "changed", configured: map[string]interface{}{"network": "host"}, current: map[string]string{"network": "bridge"}, drifted: true},
+		{name: "added", configured: map[string]interface{}{}, current: map[string]string{"network": "host"}, drifted: true},
+		{name: "removed", configured: map[string]interface{}{"network": "host"}, current: nil, drifted: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if drifted := buildxBuilderDriverOptionsDrifted(c.configured, c.current); drifted != c.drifted {
+				t.Errorf("expected drifted to be %t, got %t", c.drifted, drifted)
+			}
+		})
+	}
+}
+
+func TestBuildxBuilderConfigDrifted(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "buildkitd.toml")
+	if err := os.WriteFile(config, []byte("debug = true\n"), 0644); err != nil {
+		t.Fatalf("Failed to create buildkitd.toml: %v", err)
+	}
+	stored, err := confutil.LoadConfigFiles(config)
+	if err != nil {
+		t.Fatalf("Unable to load buildkitd.toml: %v", err)
+	}
+
+	if buildxBuilderConfigDrifted(config, stored) {
+		t.Errorf("expected an unchanged config not to drift")
+	}
+	if err := os.WriteFile(config, []byte("debug = false\n"), 0644); err != nil {
+		t.Fatalf("Failed to update buildkitd.toml: %v", err)
+	}
+	if !buildxBuilderConfigDrifted(config, stored) {
+		t.Errorf("expected a changed config to drift")
+	}
+	if !buildxBuilderConfigDrifted(filepath.Join(t.TempDir(), "missing.toml"), stored) {
+		t.Errorf("expected a missing config to drift")
+	}
+}
+
+func TestFlattenBuildxBuilderNodes(t *testing.T) {
+	nodes := []builder.Node{
+		{
+			Node: store.Node{
+				Name:           "node0",
+				Endpoint:       "unix:///var/run/docker.sock",
+				Platforms:      []ocispecs.Platform{{OS: "linux", Architecture: "arm64"}},
+				BuildkitdFlags: []string{"--debug"},
+			},
+			DriverInfo: &driver.Info{Status: driver.Running},
+			Version:    "v0.22.0",
+			Platforms: []ocispecs.Platform{
+				{OS: "linux", Architecture: "amd64"},
+				{OS: "linux", Architecture: "arm64"},
+			},
+		},
+		{
+			Node: store.Node{Name: "node1", Endpoint: "tcp://buildkitd:1234"},
+			Err:  errors.New("connection refused"),
+		},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"name":             "node0",
+			"endpoint":         "unix:///var/run/docker.sock",
+			"status":           "running",
+			"error":            "",
+			"buildkit_version": "v0.22.0",
+			"buildkit_flags":   []string{"--debug"},
+			"platforms":        []string{"linux/arm64*", "linux/amd64"},
+		},
+		map[string]interface{}{
+			"name":             "node1",
+			"endpoint":         "tcp://buildkitd:1234",
+			"status":           "",
+			"error":            "connection refused",
+			"buildkit_version": "",
+			"buildkit_flags":   []string{},
+			"platforms":        []string{},
+		},
+	}
+	if flattened := flattenBuildxBuilderNodes(nodes); !reflect.DeepEqual(flattened, expected) {
+		t.Errorf("unexpected nodes:\n%#v\nexpected:\n%#v", flattened, expected)
+	}
+}
+
+func testCheckResourceID(resourceName string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+func testCheckResourceIDUnchanged(resourceName string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		if rs.Primary.ID != *id {
+			return fmt.Errorf("expected %s to be updated in place, but it was replaced", resourceName)
+		}
+		return nil
+	}
+}
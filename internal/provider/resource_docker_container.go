@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
@@ -22,6 +23,7 @@ func resourceDockerContainer() *schema.Resource {
 		ReadContext:   resourceDockerContainerRead,
 		UpdateContext: resourceDockerContainerUpdate,
 		DeleteContext: resourceDockerContainerDelete,
+		CustomizeDiff: resourceDockerContainerCustomizeDiff,
 		MigrateState:  resourceDockerContainerMigrateState,
 		SchemaVersion: 2,
 		Importer: &schema.ResourceImporter{
@@ -49,9 +51,8 @@ func resourceDockerContainer() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
-				Description: "The name of the container.",
+				Description: "The name of the container. Changing it renames the container in place.",
 				Required:    true,
-				ForceNew:    true,
 			},
 
 			"rm": {
@@ -76,9 +77,16 @@ func resourceDockerContainer() *schema.Resource {
 				Optional:    true,
 			},
 
+			"desired_state": {
+				Type:         schema.TypeString,
+				Description:  "The state the container should be in, either `running`, `paused` or `stopped`. Changes are applied by starting, pausing, unpausing or stopping the container, and a container in another state is reported as drift. Takes precedence over `start` and `must_run` if set.",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{containerStateRunning, containerStatePaused, containerStateStopped}, false),
+			},
+
 			"wait": {
 				Type:        schema.TypeBool,
-				Description: "If `true`, then the Docker container is waited for being healthy state after creation. This requires your container to have a healthcheck, otherwise this provider will error. If `false`, then the container health state is not checked. Defaults to `false`.",
+				Description: "If `true`, then the Docker container is waited for being healthy state after creation. This requires your container to have a healthcheck, otherwise this provider will error. Use `readiness` for containers without a healthcheck. If `false`, then the container health state is not checked. Defaults to `false`.",
 				Default:     false,
 				Optional:    true,
 			},
@@ -90,6 +98,168 @@ func resourceDockerContainer() *schema.Resource {
 				Optional:    true,
 			},
 
+			"readiness": {
+				Type:        schema.TypeList,
+				Description: "Probes to wait for after the container is started, for containers without a healthcheck. The probes are run one after another in the order `log`, `tcp`, `http` and `exec`. On failure the last lines of the container logs are reported. Only used on creation if `start` is `true`.",
+				MaxItems:    1,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"log": {
+							Type:        schema.TypeList,
+							Description: "Waits for a line of the container logs to match a regular expression.",
+							MaxItems:    1,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"pattern": {
+										Type:         schema.TypeString,
+										Description:  "The regular expression a line of the logs has to match, for example `ready to accept connections`.",
+										Required:     true,
+										ValidateFunc: validation.StringIsValidRegExp,
+									},
+									"timeout": {
+										Type:             schema.TypeString,
+										Description:      "Maximum time to wait for the probe to succeed (ms|s|m|h). Defaults to `60s`.",
+										Default:          "60s",
+										Optional:         true,
+										ValidateDiagFunc: validateDurationGeq0(),
+									},
+									"interval": {
+										Type:             schema.TypeString,
+										Description:      "Time between reading the logs again if the container stopped logging (ms|s|m|h). Defaults to `1s`.",
+										Default:          "1s",
+										Optional:         true,
+										ValidateDiagFunc: validateDurationGeq0(),
+									},
+								},
+							},
+						},
+						"tcp": {
+							Type:        schema.TypeList,
+							Description: "Waits for a TCP connection to the published port of a container port to succeed and stay open. As the userland proxy of Docker accepts connections before the process in the container listens, connections which are closed within 500ms are considered failed, so servers which close connections right away never become ready.",
+							MaxItems:    1,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"port": {
+										Type:         schema.TypeInt,
+										Description:  "The port inside the container, which has to be published with `ports`.",
+										Required:     true,
+										ValidateFunc: validation.IsPortNumber,
+									},
+									"host": {
+										Type:        schema.TypeString,
+										Description: "The host to connect to. Defaults to the IP the port is published on, or the host of the Docker daemon if it is published on all interfaces.",
+										Optional:    true,
+									},
+									"timeout": {
+										Type:             schema.TypeString,
+										Description:      "Maximum time to wait for the probe to succeed (ms|s|m|h). Defaults to `60s`.",
+										Default:          "60s",
+										Optional:         true,
+										ValidateDiagFunc: validateDurationGeq0(),
+									},
+									"interval": {
+										Type:             schema.TypeString,
+										Description:      "Time between the connection attempts (ms|s|m|h). Defaults to `1s`.",
+										Default:          "1s",
+										Optional:         true,
+										ValidateDiagFunc: validateDurationGeq0(),
+									},
+								},
+							},
+						},
+						"http": {
+							Type:        schema.TypeList,
+							Description: "Waits for an HTTP GET request to the published port of a container port to return the expected status.",
+							MaxItems:    1,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"port": {
+										Type:         schema.TypeInt,
+										Description:  "The port inside the container, which has to be published with `ports`.",
+										Required:     true,
+										ValidateFunc: validation.IsPortNumber,
+									},
+									"host": {
+										Type:        schema.TypeString,
+										Description: "The host to connect to. Defaults to the IP the port is published on, or the host of the Docker daemon if it is published on all interfaces.",
+										Optional:    true,
+									},
+									"path": {
+										Type:        schema.TypeString,
+										Description: "The path of the request. Defaults to `/`.",
+										Default:     "/",
+										Optional:    true,
+									},
+									"scheme": {
+										Type:         schema.TypeString,
+										Description:  "The scheme of the request, either `http` or `https`. Defaults to `http`.",
+										Default:      "http",
+										Optional:     true,
+										ValidateFunc: validation.StringInSlice([]string{"http", "https"}, false),
+									},
+									"expected_status": {
+										Type:         schema.TypeInt,
+										Description:  "The expected status code of the response. Defaults to `200`.",
+										Default:      200,
+										Optional:     true,
+										ValidateFunc: validation.IntBetween(100, 599),
+									},
+									"timeout": {
+										Type:             schema.TypeString,
+										Description:      "Maximum time to wait for the probe to succeed (ms|s|m|h). Defaults to `60s`.",
+										Default:          "60s",
+										Optional:         true,
+										ValidateDiagFunc: validateDurationGeq0(),
+									},
+									"interval": {
+										Type:             schema.TypeString,
+										Description:      "Time between the requests (ms|s|m|h). Defaults to `1s`.",
+										Default:          "1s",
+										Optional:         true,
+										ValidateDiagFunc: validateDurationGeq0(),
+									},
+								},
+							},
+						},
+						"exec": {
+							Type:        schema.TypeList,
+							Description: "Waits for a command executed in the container to exit with code `0`.",
+							MaxItems:    1,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"command": {
+										Type:        schema.TypeList,
+										Description: "The command to execute, for example `[\"pg_isready\"]`.",
+										Required:    true,
+										MinItems:    1,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+									"timeout": {
+										Type:             schema.TypeString,
+										Description:      "Maximum time to wait for the probe to succeed (ms|s|m|h). Defaults to `60s`.",
+										Default:          "60s",
+										Optional:         true,
+										ValidateDiagFunc: validateDurationGeq0(),
+									},
+									"interval": {
+										Type:             schema.TypeString,
+										Description:      "Time between the executions (ms|s|m|h). Defaults to `1s`.",
+										Default:          "1s",
+										Optional:         true,
+										ValidateDiagFunc: validateDurationGeq0(),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
 			"attach": {
 				Type:        schema.TypeBool,
 				Description: "If `true` attach to the container after its creation and waits the end of its execution. Defaults to `false`.",
@@ -99,11 +269,61 @@ func resourceDockerContainer() *schema.Resource {
 
 			"logs": {
 				Type:        schema.TypeBool,
-				Description: "Save the container logs (`attach` must be enabled). Defaults to `false`.",
+				Description: "Save the container logs (`attach` or `job` must be enabled). Defaults to `false`.",
 				Default:     false,
 				Optional:    true,
 			},
 
+			"post_start_exec": {
+				Type:          schema.TypeList,
+				Description:   "Commands to run in the container one after another after it was created and started, after the `readiness` probes succeeded, or started again by `desired_state`, `must_run` or the `restart_on_change` of an upload, for example to bootstrap a database. Changing the commands does not run them again.",
+				Optional:      true,
+				ConflictsWith: []string{"job"},
+				Elem:          containerExecHookResource(),
+			},
+
+			"pre_stop_exec": {
+				Type:          schema.TypeList,
+				Description:   "Commands to run in the running container one after another before it is stopped by `desired_state`, restarted by the `restart_on_change` of an upload, or destroyed, for example to drain connections gracefully. The destroy fails if a command fails, unless its `on_failure` is `continue`.",
+				Optional:      true,
+				ConflictsWith: []string{"job"},
+				Elem:          containerExecHookResource(),
+			},
+
+			"job": {
+				Type:        schema.TypeList,
+				Description: "Runs the container to completion like a job on creation. The apply fails if the container exits with an exit code which is not successful, with the last lines of its logs. A completed job is not started again regardless of `must_run`, and is kept in the state even if it was removed by `rm`, in which case every change which would otherwise be applied to the existing container runs the job again in a new container. Changing the job runs it again in a new container. Can not be used together with `start = false` or a `desired_state` other than `running`.",
+				MaxItems:    1,
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"success_exit_codes": {
+							Type:        schema.TypeSet,
+							Description: "The exit codes the job is successful with. Defaults to `[0]`.",
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+						},
+						"timeout": {
+							Type:             schema.TypeString,
+							Description:      "Maximum time the job may run (ms|s|m|h). The container is killed and the apply fails when it expires. Defaults to `0s`, which means no timeout.",
+							Default:          "0s",
+							Optional:         true,
+							ForceNew:         true,
+							ValidateDiagFunc: validateDurationGeq0(),
+						},
+						"rerun_on": {
+							Type:        schema.TypeMap,
+							Description: "Arbitrary values which run the job again in a new container when they change, for example the checksum of a migration script.",
+							Optional:    true,
+							ForceNew:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
 			// Indicates whether the container must be running.
 			//
 			// An assumption is made that configured containers
@@ -128,7 +348,7 @@ func resourceDockerContainer() *schema.Resource {
 
 			"exit_code": {
 				Type:        schema.TypeInt,
-				Description: "The exit code of the container if its execution is done (`must_run` must be disabled).",
+				Description: "The exit code of the container if its execution is done (`must_run` must be disabled or `job` enabled).",
 				Computed:    true,
 			},
 
@@ -694,17 +914,20 @@ func resourceDockerContainer() *schema.Resource {
 				Type:        schema.TypeSet,
 				Description: "Limit read rate (bytes per second) from a device. This is the equivalent to repeating `--device-read-bps` for `docker run`.",
 				Optional:    true,
+				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"path": {
 							Type:        schema.TypeString,
 							Description: "The device path on the host, e.g. `/dev/sda`.",
 							Required:    true,
+							ForceNew:    true,
 						},
 						"rate": {
 							Type:             schema.TypeInt,
 							Description:      "The read rate limit in bytes per second.",
 							Required:         true,
+							ForceNew:         true,
 							ValidateDiagFunc: validateIntegerGeqThan(0),
 						},
 					},
@@ -714,17 +937,20 @@ func resourceDockerContainer() *schema.Resource {
 				Type:        schema.TypeSet,
 				Description: "Limit read rate (IO per second) from a device. This is the equivalent to repeating `--device-read-iops` for `docker run`.",
 				Optional:    true,
+				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"path": {
 							Type:        schema.TypeString,
 							Description: "The device path on the host, e.g. `/dev/sda`.",
 							Required:    true,
+							ForceNew:    true,
 						},
 						"rate": {
 							Type:             schema.TypeInt,
 							Description:      "The read IOPS limit.",
 							Required:         true,
+							ForceNew:         true,
 							ValidateDiagFunc: validateIntegerGeqThan(0),
 						},
 					},
@@ -734,17 +960,20 @@ func resourceDockerContainer() *schema.Resource {
 				Type:        schema.TypeSet,
 				Description: "Limit write rate (bytes per second) to a device. This is the equivalent to repeating `--device-write-bps` for `docker run`.",
 				Optional:    true,
+				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"path": {
 							Type:        schema.TypeString,
 							Description: "The device path on the host, e.g. `/dev/sda`.",
 							Required:    true,
+							ForceNew:    true,
 						},
 						"rate": {
 							Type:             schema.TypeInt,
 							Description:      "The write rate limit in bytes per second.",
 							Required:         true,
+							ForceNew:         true,
 							ValidateDiagFunc: validateIntegerGeqThan(0),
 						},
 					},
@@ -754,17 +983,20 @@ func resourceDockerContainer() *schema.Resource {
 				Type:        schema.TypeSet,
 				Description: "Limit write rate (IO per second) to a device. This is the equivalent to repeating `--device-write-iops` for `docker run`.",
 				Optional:    true,
+				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"path": {
 							Type:        schema.TypeString,
 							Description: "The device path on the host, e.g. `/dev/sda`.",
 							Required:    true,
+							ForceNew:    true,
 						},
 						"rate": {
 							Type:             schema.TypeInt,
 							Description:      "The write IOPS limit.",
 							Required:         true,
+							ForceNew:         true,
 							ValidateDiagFunc: validateIntegerGeqThan(0),
 						},
 					},
@@ -876,6 +1108,21 @@ func resourceDockerContainer() *schema.Resource {
 				ValidateDiagFunc: validateIntegerGeqThan(0),
 			},
 
+			"pids_limit": {
+				Type:             schema.TypeInt,
+				Description:      "The maximum number of processes in the container. Set it to `0` or `-1` for an unlimited number of processes. Defaults to the `default-pids-limit` of the Docker daemon.",
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateIntegerGeqThan(-1),
+			},
+
+			"blkio_weight": {
+				Type:         schema.TypeInt,
+				Description:  "Block IO weight (relative weight) for the container, between `10` and `1000`. `0` uses the default weight.",
+				Optional:     true,
+				ValidateFunc: validation.Any(validation.IntInSlice([]int{0}), validation.IntBetween(10, 1000)),
+			},
+
 			"cpu_set": {
 				Type:             schema.TypeString,
 				Description:      "A comma-separated list or hyphen-separated range of CPUs a container can use, e.g. `0-1`.",
@@ -908,22 +1155,19 @@ func resourceDockerContainer() *schema.Resource {
 
 			"networks_advanced": {
 				Type:        schema.TypeSet,
-				Description: "The networks the container is attached to. This is the equivalent to the ``--network`` option of `docker run`",
+				Description: "The networks the container is attached to. This is the equivalent to the ``--network`` option of `docker run`. Changes are applied by connecting and disconnecting the container, removing all networks requires a new container.",
 				Optional:    true,
-				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
 							Type:        schema.TypeString,
 							Description: "The name or id of the network to use. You can use `name` or `id` attribute from a `docker_network` resource.",
 							Required:    true,
-							ForceNew:    true,
 						},
 						"aliases": {
 							Type:        schema.TypeSet,
 							Description: "The network aliases of the container in the specific network.",
 							Optional:    true,
-							ForceNew:    true,
 							Elem:        &schema.Schema{Type: schema.TypeString},
 							Set:         schema.HashString,
 						},
@@ -931,19 +1175,16 @@ func resourceDockerContainer() *schema.Resource {
 							Type:        schema.TypeString,
 							Description: "The IPV4 address of the container in the specific network.",
 							Optional:    true,
-							ForceNew:    true,
 						},
 						"ipv6_address": {
 							Type:        schema.TypeString,
 							Description: "The IPV6 address of the container in the specific network.",
 							Optional:    true,
-							ForceNew:    true,
 						},
 						"link_local_ips": {
 							Type:        schema.TypeSet,
 							Description: "The link-local IPs of the container in the specific network. This is the equivalent to repeating `--link-local-ip` for `docker run`.",
 							Optional:    true,
-							ForceNew:    true,
 							Elem:        &schema.Schema{Type: schema.TypeString},
 							Set:         schema.HashString,
 						},
@@ -951,20 +1192,17 @@ func resourceDockerContainer() *schema.Resource {
 							Type:        schema.TypeString,
 							Description: "The MAC address of the container in the specific network.",
 							Optional:    true,
-							ForceNew:    true,
 						},
 						"driver_opts": {
 							Type:        schema.TypeSet,
 							Description: "An array of driver options for the network endpoint, e.g. `opts1=value`. This is the equivalent to repeating `--driver-opt` for `docker run`.",
 							Optional:    true,
-							ForceNew:    true,
 							Elem:        &schema.Schema{Type: schema.TypeString},
 						},
 						"gw_priority": {
 							Type:             schema.TypeInt,
 							Description:      "Gateway priority for this endpoint. The endpoint with the highest priority will provide the default gateway for the container. This is the equivalent to `--gw-priority` for `docker run`.",
 							Optional:         true,
-							ForceNew:         true,
 							ValidateDiagFunc: validateIntegerGeqThan(0),
 						},
 					},
@@ -986,62 +1224,87 @@ func resourceDockerContainer() *schema.Resource {
 
 			"upload": {
 				Type:        schema.TypeSet,
-				Description: "Specifies files to upload to the container before starting it. Only one of `content` or `content_base64` can be set and at least one of them has to be set.",
+				Description: "Specifies files to upload to the container before starting it. Exactly one of `content`, `content_base64`, `source` or `source_archive` has to be set. Changed files are uploaded into the existing container, the files of removed uploads are left in the container.",
 				Optional:    true,
-				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"content": {
 							Type:        schema.TypeString,
-							Description: "Literal string value to use as the object content, which will be uploaded as UTF-8-encoded text. Conflicts with `content_base64` & `source`",
+							Description: "Literal string value to use as the object content, which will be uploaded as UTF-8-encoded text. Conflicts with `content_base64`, `source` & `source_archive`",
 							Optional:    true,
-							// This is intentional. The container is mutated once, and never updated later.
-							// New configuration forces a new deployment, even with the same binaries.
-							ForceNew: true,
 						},
 						"content_base64": {
 							Type:             schema.TypeString,
-							Description:      "Base64-encoded data that will be decoded and uploaded as raw bytes for the object content. This allows safely uploading non-UTF8 binary data, but is recommended only for larger binary content such as the result of the `base64encode` interpolation function. See [here](https://github.com/terraform-providers/terraform-provider-docker/issues/48#issuecomment-374174588) for the reason. Conflicts with `content` & `source`",
+							Description:      "Base64-encoded data that will be decoded and uploaded as raw bytes for the object content. This allows safely uploading non-UTF8 binary data, but is recommended only for larger binary content such as the result of the `base64encode` interpolation function. See [here](https://github.com/terraform-providers/terraform-provider-docker/issues/48#issuecomment-374174588) for the reason. Conflicts with `content`, `source` & `source_archive`",
 							Optional:         true,
-							ForceNew:         true,
 							ValidateDiagFunc: validateStringIsBase64Encoded(),
 						},
 						"file": {
 							Type:        schema.TypeString,
-							Description: "Path to the file in the container where is upload goes to",
+							Description: "Path to the file in the container where is upload goes to. If `source` is a directory or `source_archive` is set, this is the directory in the container the files are extracted into.",
 							Required:    true,
-							ForceNew:    true,
 						},
 						"executable": {
 							Type:        schema.TypeBool,
-							Description: "If `true`, the file will be uploaded with user executable permission. Defaults to `false`.",
+							Description: "If `true`, the file will be uploaded with user executable permission. Ignored if `source` is a directory or `source_archive` is set. Defaults to `false`.",
 							Default:     false,
 							Optional:    true,
-							ForceNew:    true,
 						},
 						"permissions": {
 							Type:             schema.TypeString,
-							Description:      "The permission mode for the file in the container. Has precedence over `executable`.",
+							Description:      "The permission mode for the file in the container. Has precedence over `executable`. Ignored if `source` is a directory or `source_archive` is set.",
 							Optional:         true,
-							ForceNew:         true,
 							ValidateDiagFunc: validateStringMatchesPattern(`^0[0-7]{3}$`),
 						},
 						"source": {
 							Type:        schema.TypeString,
-							Description: "A filename that references a file which will be uploaded as the object content. This allows for large file uploads that do not get stored in state. Conflicts with `content` & `content_base64`",
+							Description: "A filename that references a file which will be uploaded as the object content. This allows for large file uploads that do not get stored in state. If it is a directory, its files are uploaded recursively with their modes. Conflicts with `content`, `content_base64` & `source_archive`",
+							Optional:    true,
+						},
+						"source_archive": {
+							Type:        schema.TypeString,
+							Description: "Path to a tar archive, which may be compressed with gzip, bzip2, xz or zstd, that is extracted into the `file` directory in the container. Conflicts with `content`, `content_base64` & `source`",
+							Optional:    true,
+						},
+						"include": {
+							Type:        schema.TypeList,
+							Description: "If `source` is a directory, only the files matching one of these patterns are uploaded. The patterns are relative to `source` and use the syntax of `.dockerignore` files.",
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"exclude": {
+							Type:        schema.TypeList,
+							Description: "If `source` is a directory, the files matching one of these patterns are not uploaded. The patterns are relative to `source` and use the syntax of `.dockerignore` files.",
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"restart_on_change": {
+							Type:        schema.TypeBool,
+							Description: "If `true`, the running container is restarted after the file was uploaded again because it changed. Has precedence over `signal_on_change`. Defaults to `false`.",
+							Default:     false,
+							Optional:    true,
+						},
+						"signal_on_change": {
+							Type:        schema.TypeString,
+							Description: "The signal to send to the running container after the file was uploaded again because it changed, for example `SIGHUP` to reload the configuration of nginx.",
 							Optional:    true,
-							ForceNew:    true,
 						},
 						"source_hash": {
 							Type:        schema.TypeString,
-							Description: "If using `source`, this will force an update if the file content has updated but the filename has not. ",
+							Description: "If using `source`, this will upload the file again if the file content has updated but the filename has not. ",
 							Optional:    true,
-							ForceNew:    true,
 						},
 					},
 				},
 			},
 
+			"upload_sha256": {
+				Type:        schema.TypeMap,
+				Description: "The sha256 hashes of the contents of the uploads by their `file`. A changed hash uploads the files again, also if only the files in a `source` directory or archive changed.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
 			"healthcheck": {
 				Type:        schema.TypeList,
 				Description: "A test to perform to check that the container is healthy",
@@ -1157,9 +1420,8 @@ func resourceDockerContainer() *schema.Resource {
 			},
 			"cpus": {
 				Type:        schema.TypeString,
-				Description: "Specify how much of the available CPU resources a container can use. e.g a value of 1.5 means the container is guaranteed at most one and a half of the CPUs. Has precedence over `cpu_period` and `cpu_quota`.",
+				Description: "Specify how much of the available CPU resources a container can use. e.g a value of 1.5 means the container is guaranteed at most one and a half of the CPUs. Has precedence over `cpu_period` and `cpu_quota`. Changing it updates the container in place, unless it is removed or replaces `cpu_period` and `cpu_quota`.",
 				Optional:    true,
-				ForceNew:    true,
 			},
 			"cpu_period": {
 				Type:             schema.TypeInt,
@@ -0,0 +1,215 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	containerPostStartExec = "post_start_exec"
+	containerPreStopExec   = "pre_stop_exec"
+
+	containerExecHookOnFailureFail     = "fail"
+	containerExecHookOnFailureContinue = "continue"
+)
+
+// containerExecHookResource is the schema of the commands which are run in the container
+// after it is started and before it is stopped.
+func containerExecHookResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"command": {
+				Type:        schema.TypeList,
+				Description: "The command and its arguments to run in the container, for example `[\"psql\", \"-f\", \"/docker-entrypoint-initdb.d/bootstrap.sql\"]`.",
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"env": {
+				Type:        schema.TypeSet,
+				Description: "Environment variables to set for the command, in the form of `KEY=value`, or `KEY` to pass the variable of the environment Terraform runs in.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Description: "The user to run the command as, in the form of `<name|uid>[:<group|gid>]`. Defaults to the user of the container.",
+				Optional:    true,
+			},
+			"workdir": {
+				Type:        schema.TypeString,
+				Description: "The working directory of the command. Defaults to the working directory of the container.",
+				Optional:    true,
+			},
+			"privileged": {
+				Type:        schema.TypeBool,
+				Description: "If `true`, the command is run with extended privileges. Defaults to `false`.",
+				Default:     false,
+				Optional:    true,
+			},
+			"success_exit_codes": {
+				Type:        schema.TypeSet,
+				Description: "The exit codes the command is successful with. Defaults to `[0]`.",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"timeout": {
+				Type:             schema.TypeString,
+				Description:      "Maximum time to wait for the command (ms|s|m|h). The command is not killed in the container when it expires. Defaults to `60s`, `0s` means no timeout.",
+				Default:          "60s",
+				Optional:         true,
+				ValidateDiagFunc: validateDurationGeq0(),
+			},
+			"on_failure": {
+				Type:         schema.TypeString,
+				Description:  "What to do if the command fails: `fail` fails the apply, `continue` only reports a warning. Defaults to `fail`.",
+				Default:      containerExecHookOnFailureFail,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{containerExecHookOnFailureFail, containerExecHookOnFailureContinue}, false),
+			},
+		},
+	}
+}
+
+// runContainerExecHooks runs the commands of the hook in the container one after another, and
+// stops at the first one which fails unless it is allowed to fail.
+func runContainerExecHooks(ctx context.Context, client *client.Client, d *schema.ResourceData, hook string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for i, rawHook := range d.Get(hook).([]interface{}) {
+		if rawHook == nil {
+			continue
+		}
+		diags = append(diags, runContainerExecHook(ctx, client, d.Id(), fmt.Sprintf("%s.%d", hook, i), rawHook.(map[string]interface{}))...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+	return diags
+}
+
+// runContainerExecHook runs a single command of a hook. Its output is logged, and reported with
+// the diagnostics if it fails.
+func runContainerExecHook(ctx context.Context, client *client.Client, containerID, hook string, config map[string]interface{}) diag.Diagnostics {
+	command := stringListToStringSlice(config["command"].([]interface{}))
+	successExitCodes := containerExecHookSuccessExitCodes(config)
+	// the timeout is validated by the schema
+	timeout, _ := time.ParseDuration(config["timeout"].(string))
+
+	execOptions := container.ExecOptions{
+		User:         config["user"].(string),
+		Privileged:   config["privileged"].(bool),
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          resolveEnvironmentVariables(stringSetToStringSlice(config["env"].(*schema.Set))),
+		WorkingDir:   config["workdir"].(string),
+		Cmd:          command,
+	}
+
+	log.Printf("[INFO] Running %s %q in container %s", hook, strings.Join(command, " "), containerID)
+	result, err := func() (*containerExecResult, error) {
+		var execCtx context.Context
+		var cancel context.CancelFunc
+		if timeout > 0 {
+			execCtx, cancel = context.WithTimeout(ctx, timeout)
+		} else {
+			execCtx, cancel = context.WithCancel(ctx)
+		}
+		defer cancel()
+
+		execCreateResponse, err := client.ContainerExecCreate(execCtx, containerID, execOptions)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create exec: %w", err)
+		}
+		result, err := runContainerExec(execCtx, client, execCreateResponse.ID, false)
+		if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+			return result, fmt.Errorf("the command did not complete within %s", timeout)
+		}
+		return result, err
+	}()
+
+	var output string
+	if result != nil {
+		output = strings.TrimRight(result.stdout.String()+result.stderr.String(), "\n")
+		tflog.Info(ctx, "Container exec hook output", map[string]interface{}{
+			"hook":         hook,
+			"container_id": containerID,
+			"command":      command,
+			"exit_code":    result.exitCode,
+			"stdout":       result.stdout.String(),
+			"stderr":       result.stderr.String(),
+		})
+	}
+
+	var detail string
+	switch {
+	case err != nil:
+		detail = err.Error()
+	case !slices.Contains(successExitCodes, result.exitCode):
+		detail = fmt.Sprintf("The command exited with code %d, the successful exit codes are %v.", result.exitCode, successExitCodes)
+	default:
+		log.Printf("[DEBUG] %s of container %s exited with code %d", hook, containerID, result.exitCode)
+		return nil
+	}
+
+	if tail := lastLogLines(output, containerDiagnosticLogLines); tail != "" {
+		detail += fmt.Sprintf("\n\nLast %d lines of the output of the command:\n%s", containerDiagnosticLogLines, tail)
+	}
+	severity := diag.Error
+	if config["on_failure"].(string) == containerExecHookOnFailureContinue {
+		severity = diag.Warning
+	}
+	return diag.Diagnostics{{
+		Severity: severity,
+		Summary:  fmt.Sprintf("Command %s %q failed in container %s", hook, strings.Join(command, " "), containerID),
+		Detail:   detail,
+	}}
+}
+
+// containerExecHookSuccessExitCodes returns the exit codes the command of the hook is
+// successful with.
+func containerExecHookSuccessExitCodes(config map[string]interface{}) []int {
+	exitCodes := []int{}
+	if rawExitCodes, ok := config["success_exit_codes"].(*schema.Set); ok {
+		for _, exitCode := range rawExitCodes.List() {
+			exitCodes = append(exitCodes, exitCode.(int))
+		}
+	}
+	if len(exitCodes) == 0 {
+		return []int{0}
+	}
+	slices.Sort(exitCodes)
+	return exitCodes
+}
+
+// runContainerPreStopExecHooks runs the pre_stop_exec hooks if the container is running, as
+// commands can not be run in stopped or paused containers.
+func runContainerPreStopExecHooks(ctx context.Context, client *client.Client, d *schema.ResourceData) diag.Diagnostics {
+	if len(d.Get(containerPreStopExec).([]interface{})) == 0 {
+		return nil
+	}
+
+	inspected, err := client.ContainerInspect(ctx, d.Id())
+	if err != nil {
+		if containsIgnorableErrorMessage(err.Error(), "No such container") {
+			return nil
+		}
+		return diag.Errorf("Unable to inspect container %s: %s", d.Id(), err)
+	}
+	if inspected.State == nil || !inspected.State.Running || inspected.State.Paused {
+		log.Printf("[DEBUG] Container %s is not running, skipping its %s hooks", d.Id(), containerPreStopExec)
+		return nil
+	}
+	return runContainerExecHooks(ctx, client, d, containerPreStopExec)
+}
@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestContainerExecHookSuccessExitCodes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected []int
+	}{
+		{name: "default", config: map[string]interface{}{}, expected: []int{0}},
+		{name: "empty", config: map[string]interface{}{"success_exit_codes": schema.NewSet(schema.HashInt, nil)}, expected: []int{0}},
+		{name: "configured", config: map[string]interface{}{"success_exit_codes": schema.NewSet(schema.HashInt, []interface{}{3, 0, 1})}, expected: []int{0, 1, 3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := containerExecHookSuccessExitCodes(tc.config); !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestContainerExecHookSchema(t *testing.T) {
+	hook := resourceDockerContainer().Schema[containerPostStartExec].Elem.(*schema.Resource)
+	data := schema.TestResourceDataRaw(t, hook.Schema, map[string]interface{}{
+		"command": []interface{}{"true"},
+	})
+
+	if timeout := data.Get("timeout").(string); timeout != "60s" {
+		t.Fatalf("expected the default timeout to be 60s, got %s", timeout)
+	}
+	if onFailure := data.Get("on_failure").(string); onFailure != containerExecHookOnFailureFail {
+		t.Fatalf("expected the default on_failure to be %s, got %s", containerExecHookOnFailureFail, onFailure)
+	}
+}
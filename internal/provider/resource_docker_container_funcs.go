@@ -1,17 +1,16 @@
 package provider
 
 import (
-	"archive/tar"
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"strconv"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,6 +35,12 @@ const (
 	containerReadRefreshDelay                      = 100 * time.Millisecond
 )
 
+const (
+	containerStateRunning = "running"
+	containerStatePaused  = "paused"
+	containerStateStopped = "stopped"
+)
+
 var (
 	errContainerFailedToBeInHealthyState = errors.New("container failed to be in healthy state")
 )
@@ -384,6 +389,16 @@ func resourceDockerContainerCreate(ctx context.Context, d *schema.ResourceData,
 		hostConfig.CpusetCpus = v.(string)
 	}
 
+	// GetOk would drop an explicit 0, which disables the default limit of the daemon
+	if v, ok := d.GetOkExists("pids_limit"); ok { //nolint:staticcheck
+		pidsLimit := int64(v.(int))
+		hostConfig.PidsLimit = &pidsLimit
+	}
+
+	if v, ok := d.GetOk("blkio_weight"); ok {
+		hostConfig.BlkioWeight = uint16(v.(int))
+	}
+
 	if v, ok := d.GetOk("log_opts"); ok {
 		hostConfig.LogConfig.Config = mapTypeMapValsToString(v.(map[string]interface{}))
 	}
@@ -469,32 +484,7 @@ func resourceDockerContainerCreate(ctx context.Context, d *schema.ResourceData,
 
 		for _, rawNetwork := range v.(*schema.Set).List() {
 			networkID := rawNetwork.(map[string]interface{})["name"].(string)
-
-			endpointConfig := &network.EndpointSettings{}
-			endpointIPAMConfig := &network.EndpointIPAMConfig{}
-			if v, ok := rawNetwork.(map[string]interface{})["aliases"]; ok {
-				endpointConfig.Aliases = stringSetToStringSlice(v.(*schema.Set))
-			}
-			if v, ok := rawNetwork.(map[string]interface{})["ipv4_address"]; ok {
-				endpointIPAMConfig.IPv4Address = v.(string)
-			}
-			if v, ok := rawNetwork.(map[string]interface{})["ipv6_address"]; ok {
-				endpointIPAMConfig.IPv6Address = v.(string)
-			}
-			if v, ok := rawNetwork.(map[string]interface{})["link_local_ips"]; ok {
-				endpointIPAMConfig.LinkLocalIPs = stringSetToStringSlice(v.(*schema.Set))
-			}
-			endpointConfig.IPAMConfig = endpointIPAMConfig
-
-			if v, ok := rawNetwork.(map[string]interface{})["mac_address"]; ok {
-				endpointConfig.MacAddress = v.(string)
-			}
-			if v, ok := rawNetwork.(map[string]interface{})["driver_opts"]; ok {
-				endpointConfig.DriverOpts = stringSetToMapStringString(v.(*schema.Set))
-			}
-			if v, ok := rawNetwork.(map[string]interface{})["gw_priority"]; ok {
-				endpointConfig.GwPriority = v.(int)
-			}
+			endpointConfig := networkAdvancedToEndpointSettings(rawNetwork.(map[string]interface{}))
 
 			if err := client.NetworkConnect(ctx, networkID, retContainer.ID, endpointConfig); err != nil {
 				return diag.Errorf("Unable to connect to network '%s': %s", networkID, err)
@@ -503,85 +493,26 @@ func resourceDockerContainerCreate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	if v, ok := d.GetOk("upload"); ok {
+		if err := uploadToContainer(ctx, client, retContainer.ID, v.(*schema.Set).List()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	uploadHashes, err := containerUploadHashes(ctx, d.Get("upload").(*schema.Set).List())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("upload_sha256", uploadHashes)
 
-		var mode int64
-		for _, upload := range v.(*schema.Set).List() {
-			content := upload.(map[string]interface{})["content"].(string)
-			contentBase64 := upload.(map[string]interface{})["content_base64"].(string)
-			source := upload.(map[string]interface{})["source"].(string)
-
-			testParams := []string{content, contentBase64, source}
-			setParams := 0
-			for _, v := range testParams {
-				if v != "" {
-					setParams++
-				}
-			}
-
-			if setParams == 0 {
-				return diag.Errorf("error with upload content: one of 'content', 'content_base64', or 'source' must be set")
-			}
-			if setParams > 1 {
-				return diag.Errorf("error with upload content: only one of 'content', 'content_base64', or 'source' can be set")
-			}
-
-			var contentToUpload string
-			if content != "" {
-				contentToUpload = content
-			}
-			if contentBase64 != "" {
-				decoded, _ := base64.StdEncoding.DecodeString(contentBase64)
-				contentToUpload = string(decoded)
-			}
-			if source != "" {
-				sourceContent, err := os.ReadFile(source)
-				if err != nil {
-					return diag.Errorf("could not read file: %s", err)
-				}
-				contentToUpload = string(sourceContent)
-			}
-			file := upload.(map[string]interface{})["file"].(string)
-			executable := upload.(map[string]interface{})["executable"].(bool)
-			permission := upload.(map[string]interface{})["permissions"].(string)
-
-			buf := new(bytes.Buffer)
-			tw := tar.NewWriter(buf)
-			if permission != "" {
-				mode, err = strconv.ParseInt(permission, 8, 32)
-				if err != nil {
-					return diag.Errorf("Error parsing permission: %s", err)
-				}
-			} else if executable {
-				mode = 0o744
-			} else {
-				mode = 0o644
-			}
-			hdr := &tar.Header{
-				Name:    file,
-				Mode:    mode,
-				Size:    int64(len(contentToUpload)),
-				ModTime: time.Now(),
-			}
-			if err := tw.WriteHeader(hdr); err != nil {
-				return diag.Errorf("Error creating tar archive: %s", err)
-			}
-			if _, err := tw.Write([]byte(contentToUpload)); err != nil {
-				return diag.Errorf("Error creating tar archive: %s", err)
-			}
-			if err := tw.Close(); err != nil {
-				return diag.Errorf("Error creating tar archive: %s", err)
-			}
-
-			dstPath := "/"
-			uploadContent := bytes.NewReader(buf.Bytes())
-			options := container.CopyToContainerOptions{}
-			if err := client.CopyToContainer(ctx, retContainer.ID, dstPath, uploadContent, options); err != nil {
-				return diag.Errorf("Unable to upload volume content: %s", err)
+	var diags diag.Diagnostics
+	var job *containerJob
+	if containerShouldStart(d) {
+		if containerIsJob(d) {
+			if job, err = attachContainerJob(ctx, client, d); err != nil {
+				return diag.FromErr(err)
 			}
+			defer job.close()
 		}
-	}
 
-	if d.Get("start").(bool) {
 		options := container.StartOptions{}
 		if err := client.ContainerStart(ctx, retContainer.ID, options); err != nil {
 			return diag.Errorf("Unable to start container: %s", err)
@@ -626,9 +557,22 @@ func resourceDockerContainerCreate(ctx context.Context, d *schema.ResourceData,
 				}
 			}
 		}
+
+		if diags := waitForContainerReadiness(ctx, client, d); diags.HasError() {
+			return diags
+		}
+
+		diags = runContainerExecHooks(ctx, client, d, containerPostStartExec)
+		if diags.HasError() {
+			return diags
+		}
 	}
 
-	if d.Get("attach").(bool) {
+	if job != nil {
+		if diags := runContainerJob(ctx, client, d, job); diags.HasError() {
+			return diags
+		}
+	} else if d.Get("attach").(bool) {
 		var b bytes.Buffer
 		logsDone := make(chan error, 1)
 		if d.Get("logs").(bool) {
@@ -670,7 +614,13 @@ func resourceDockerContainerCreate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	return resourceDockerContainerRead(ctx, d, meta)
+	if d.Get("desired_state").(string) == containerStatePaused {
+		if err := client.ContainerPause(ctx, retContainer.ID); err != nil {
+			return append(diags, diag.Errorf("Unable to pause container: %s", err)...)
+		}
+	}
+
+	return append(diags, resourceDockerContainerRead(ctx, d, meta)...)
 }
 
 func copyContainerLogs(dst io.Writer, reader io.Reader, tty bool) error {
@@ -767,6 +717,11 @@ func resourceDockerContainerRead(ctx context.Context, d *schema.ResourceData, me
 		return diag.FromErr(err)
 	}
 	if apiContainer == nil {
+		if containerIsJob(d) && d.Get("rm").(bool) {
+			// the container of a completed job is removed by the daemon
+			log.Printf("[DEBUG] Container %s of the job was removed after its completion", d.Id())
+			return nil
+		}
 		// This container doesn't exist anymore
 		d.SetId("")
 		return nil
@@ -797,7 +752,7 @@ func resourceDockerContainerRead(ctx context.Context, d *schema.ResourceData, me
 		// if the container exited, NetworkSettings.Ports is nil
 		// if we do not need to start the container (must_run is false), we simply do not set the ports with the empty value
 		// That way we can mitigate the bug from https://github.com/kreuzwerker/terraform-provider-docker/issues/77
-		if container.State.Running || d.Get("must_run").(bool) {
+		if container.State.Running || containerMustRun(d) {
 			if _, ok := d.GetOk("ports"); ok {
 				if err := d.Set("ports", flattenContainerPorts(container.NetworkSettings.Ports)); err != nil {
 					log.Printf("[WARN] failed to set ports from API: %s", err)
@@ -817,12 +772,18 @@ func resourceDockerContainerRead(ctx context.Context, d *schema.ResourceData, me
 
 		// If must_run is configured as true but container is stopped, set it to false in state
 		// This will show as a change in terraform plan and trigger Update on apply
-		if d.Get("must_run").(bool) {
+		// The container of a job is not started again though, and the desired_state reports
+		// the drift itself
+		if d.Get("must_run").(bool) && !containerIsJob(d) && d.Get("desired_state").(string) == "" {
 			log.Printf("[WARN] Container %s is stopped but must_run is configured as true. Setting must_run to false in state to trigger update.", container.ID)
 			d.Set("must_run", false)
 		}
 	}
 
+	if d.Get("desired_state").(string) != "" {
+		d.Set("desired_state", containerStateOf(container.State))
+	}
+
 	// TODO all the other attributes
 	d.SetId(container.ID)
 	d.Set("name", strings.TrimLeft(container.Name, "/")) // api prefixes with '/' ...
@@ -945,9 +906,20 @@ func resourceDockerContainerRead(ctx context.Context, d *schema.ResourceData, me
 	d.Set("shm_size", container.HostConfig.ShmSize/1024/1024)
 	if container.HostConfig.NanoCPUs > 0 {
 		d.Set("cpus", nanoInt64ToDecimalString(container.HostConfig.NanoCPUs))
+	} else {
+		// cpu_period and cpu_quota are ignored if cpus is set
+		d.Set("cpu_period", container.HostConfig.CPUPeriod)
+		d.Set("cpu_quota", container.HostConfig.CPUQuota)
 	}
 	d.Set("cpu_shares", container.HostConfig.CPUShares)
 	d.Set("cpu_set", container.HostConfig.CpusetCpus)
+	if container.HostConfig.PidsLimit != nil && *container.HostConfig.PidsLimit > 0 {
+		d.Set("pids_limit", *container.HostConfig.PidsLimit)
+	} else if d.Get("pids_limit").(int) != -1 {
+		// -1 and 0 both mean unlimited
+		d.Set("pids_limit", 0)
+	}
+	d.Set("blkio_weight", int(container.HostConfig.BlkioWeight))
 	d.Set("log_driver", container.HostConfig.LogConfig.Type)
 	d.Set("log_opts", containerLogOptsForState(d, container.HostConfig.LogConfig.Config))
 	d.Set("storage_opts", container.HostConfig.StorageOpt)
@@ -994,6 +966,14 @@ func resourceDockerContainerUpdate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(fmt.Errorf("failed to create Docker client: %w", err))
 	}
 
+	var diags diag.Diagnostics
+	if d.HasChange("name") {
+		diags = renameContainer(ctx, client, d)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
 	// Check if must_run changed from false to true (container was stopped, now should be running)
 	if d.HasChange("must_run") {
 		old, new := d.GetChange("must_run")
@@ -1003,85 +983,451 @@ func resourceDockerContainerUpdate(ctx context.Context, d *schema.ResourceData,
 
 			// Check if start is enabled
 			if d.Get("start").(bool) {
-				options := container.StartOptions{}
-				if err := client.ContainerStart(ctx, d.Id(), options); err != nil {
-					return diag.Errorf("Unable to start container: %s", err)
+				inspected, err := client.ContainerInspect(ctx, d.Id())
+				if err != nil {
+					return append(diags, diag.Errorf("Unable to inspect container: %s", err)...)
+				}
+				// the hooks only run if the container is actually started
+				if inspected.State == nil || !inspected.State.Running {
+					options := container.StartOptions{}
+					if err := client.ContainerStart(ctx, d.Id(), options); err != nil {
+						return append(diags, diag.Errorf("Unable to start container: %s", err)...)
+					}
+					log.Printf("[INFO] Successfully started container %s", d.Id())
+
+					diags = append(diags, runContainerExecHooks(ctx, client, d, containerPostStartExec)...)
+					if diags.HasError() {
+						return diags
+					}
 				}
-				log.Printf("[INFO] Successfully started container %s", d.Id())
 			}
 		}
 	}
 
-	// Handle other attribute updates
-	attrs := []string{
-		"restart", "max_retry_count", "cpu_shares", "memory", "memory_reservation", "cpu_set", "memory_swap",
-		"device_read_bps", "device_read_iops", "device_write_bps", "device_write_iops",
+	if d.HasChange("desired_state") {
+		if desiredState := d.Get("desired_state").(string); desiredState != "" {
+			diags = append(diags, applyContainerDesiredState(ctx, client, d, desiredState)...)
+			if diags.HasError() {
+				return diags
+			}
+		}
 	}
-	for _, attr := range attrs {
-		if d.HasChange(attr) {
 
-			// TODO update ulimits
-			// Updating ulimits seems not to work well.
-			// It succeeds to run `DockerClient.ContainerUpdate` with `ulimit` but actually `ulimit` aren't changed.
-			// https://github.com/terraform-providers/terraform-provider-docker/pull/236#discussion_r373819536
-			// ulimits := []*units.Ulimit{}
-			// if v, ok := d.GetOk("ulimit"); ok {
-			// 	ulimits = ulimitsToDockerUlimits(v.(*schema.Set))
-			// }
+	if d.HasChanges("upload", "upload_sha256") {
+		diags = append(diags, updateContainerUploads(ctx, client, d)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
 
-			resources := container.Resources{
-				CPUShares:         int64(d.Get("cpu_shares").(int)),
-				Memory:            int64(d.Get("memory").(int)) * 1024 * 1024,
-				MemoryReservation: int64(d.Get("memory_reservation").(int)) * 1024 * 1024,
-				CpusetCpus:        d.Get("cpu_set").(string),
-				// Ulimits:    ulimits,
-			}
-			if v, ok := d.GetOk("device_read_bps"); ok {
-				resources.BlkioDeviceReadBps = throttleDeviceSetToDockerThrottleDevices(v.(*schema.Set))
-			}
-			if v, ok := d.GetOk("device_read_iops"); ok {
-				resources.BlkioDeviceReadIOps = throttleDeviceSetToDockerThrottleDevices(v.(*schema.Set))
+	// Handle the attributes the daemon can update on the running container
+	if d.HasChanges(containerUpdateAttributes...) {
+		updateConfig, err := containerUpdateConfig(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("[INFO] Updating resources of container %s", d.Id())
+		_, err = client.ContainerUpdate(ctx, d.Id(), updateConfig)
+		if err != nil {
+			return diag.Errorf("Unable to update a container: %v", err)
+		}
+	}
+
+	if d.HasChange("networks_advanced") {
+		if err := updateContainerNetworks(ctx, client, d); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	if d.HasChanges("name", "networks_advanced") {
+		// refresh the network_data of the renamed or reconnected container
+		return append(diags, resourceDockerContainerRead(ctx, d, meta)...)
+	}
+	return diags
+}
+
+// renameContainer renames the container to its new name. The daemon updates the DNS names of
+// running containers on user-defined networks, which is verified, as other containers of the
+// network would not resolve the new name otherwise.
+func renameContainer(ctx context.Context, client *client.Client, d *schema.ResourceData) diag.Diagnostics {
+	oldName, newName := d.GetChange("name")
+	log.Printf("[INFO] Renaming container %s from %s to %s", d.Id(), oldName, newName)
+	if err := client.ContainerRename(ctx, d.Id(), newName.(string)); err != nil {
+		return diag.Errorf("Unable to rename container %s from %s to %s: %s", d.Id(), oldName, newName, err)
+	}
+
+	inspected, err := client.ContainerInspect(ctx, d.Id())
+	if err != nil {
+		return diag.Errorf("Unable to inspect renamed container %s: %s", d.Id(), err)
+	}
+
+	var diags diag.Diagnostics
+	for _, networkName := range containerNetworksMissingDNSName(inspected, newName.(string)) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Renamed container is not resolvable by its new name",
+			Detail:   fmt.Sprintf("The DNS names of container %s in network %s do not contain its new name %s. Restart the container to update them.", d.Id(), networkName, newName),
+		})
+	}
+	return diags
+}
+
+// containerNetworksMissingDNSName returns the user-defined networks of the running container
+// whose DNS names do not contain name. DNS names are only reported since API version 1.44.
+func containerNetworksMissingDNSName(inspected container.InspectResponse, name string) []string {
+	if inspected.State == nil || !inspected.State.Running || inspected.NetworkSettings == nil {
+		return nil
+	}
+
+	var networkNames []string
+	for networkName, endpoint := range inspected.NetworkSettings.Networks {
+		if endpoint == nil || len(endpoint.DNSNames) == 0 {
+			continue
+		}
+		if !slices.Contains(endpoint.DNSNames, name) {
+			networkNames = append(networkNames, networkName)
+		}
+	}
+	sort.Strings(networkNames)
+	return networkNames
+}
+
+// updateContainerNetworks connects the container to the added networks and disconnects it from
+// the removed ones. Changed networks are disconnected and reconnected with the new endpoint
+// settings. The added networks are connected before the removed ones are disconnected, so
+// the container stays reachable while it is moved between networks.
+func updateContainerNetworks(ctx context.Context, client *client.Client, d *schema.ResourceData) error {
+	oldNetworks, newNetworks := d.GetChange("networks_advanced")
+	removed := oldNetworks.(*schema.Set).Difference(newNetworks.(*schema.Set)).List()
+	added := newNetworks.(*schema.Set).Difference(oldNetworks.(*schema.Set)).List()
+
+	addedNames := make(map[string]bool, len(added))
+	for _, rawNetwork := range added {
+		addedNames[rawNetwork.(map[string]interface{})["name"].(string)] = true
+	}
+
+	if oldNetworks.(*schema.Set).Len() == 0 {
+		// the container was created without networks_advanced, so it is still connected to the
+		// network of its network_mode
+		if err := disconnectContainerNetworkMode(ctx, client, d.Id(), d.Get("network_mode").(string)); err != nil {
+			return err
+		}
+	}
+
+	for _, rawNetwork := range removed {
+		networkID := rawNetwork.(map[string]interface{})["name"].(string)
+		if !addedNames[networkID] {
+			continue
+		}
+		log.Printf("[INFO] Disconnecting container %s from changed network %s", d.Id(), networkID)
+		if err := client.NetworkDisconnect(ctx, networkID, d.Id(), false); err != nil {
+			return fmt.Errorf("unable to disconnect container %s from network '%s': %w", d.Id(), networkID, err)
+		}
+	}
+
+	for _, rawNetwork := range added {
+		networkID := rawNetwork.(map[string]interface{})["name"].(string)
+		log.Printf("[INFO] Connecting container %s to network %s", d.Id(), networkID)
+		endpointConfig := networkAdvancedToEndpointSettings(rawNetwork.(map[string]interface{}))
+		if err := client.NetworkConnect(ctx, networkID, d.Id(), endpointConfig); err != nil {
+			return fmt.Errorf("unable to connect container %s to network '%s': %w", d.Id(), networkID, err)
+		}
+	}
+
+	for _, rawNetwork := range removed {
+		networkID := rawNetwork.(map[string]interface{})["name"].(string)
+		if addedNames[networkID] {
+			continue
+		}
+		log.Printf("[INFO] Disconnecting container %s from network %s", d.Id(), networkID)
+		if err := client.NetworkDisconnect(ctx, networkID, d.Id(), false); err != nil {
+			if !containsIgnorableErrorMessage(err.Error(), "is not connected to") {
+				return fmt.Errorf("unable to disconnect container %s from network '%s': %w", d.Id(), networkID, err)
 			}
-			if v, ok := d.GetOk("device_write_bps"); ok {
-				resources.BlkioDeviceWriteBps = throttleDeviceSetToDockerThrottleDevices(v.(*schema.Set))
+		}
+	}
+
+	return nil
+}
+
+// disconnectContainerNetworkMode disconnects a container created without networks_advanced from
+// the network of its network mode, which is replaced by the networks of networks_advanced. Containers
+// which use the network stack of the host or of another container, or none at all, are not
+// connected to a network.
+func disconnectContainerNetworkMode(ctx context.Context, client *client.Client, containerID string, networkMode string) error {
+	networkName, ok := containerNetworkModeNetwork(container.NetworkMode(networkMode))
+	if !ok {
+		return nil
+	}
+
+	log.Printf("[INFO] Disconnecting container %s from network %s of its network mode", containerID, networkName)
+	if err := client.NetworkDisconnect(ctx, networkName, containerID, false); err != nil {
+		if !containsIgnorableErrorMessage(err.Error(), "is not connected to the network") {
+			return fmt.Errorf("unable to disconnect container %s from network '%s': %w", containerID, networkName, err)
+		}
+	}
+	return nil
+}
+
+// containerNetworkModeNetwork returns the network a container with the network mode is
+// connected to when it is created, if any.
+func containerNetworkModeNetwork(networkMode container.NetworkMode) (string, bool) {
+	switch {
+	case networkMode.IsHost() || networkMode.IsNone() || networkMode.IsContainer():
+		return "", false
+	case networkMode == "" || networkMode.IsDefault():
+		return network.NetworkBridge, true
+	default:
+		return networkMode.NetworkName(), true
+	}
+}
+
+// containerShouldStart returns whether the container is started after its creation.
+func containerShouldStart(d *schema.ResourceData) bool {
+	if desiredState := d.Get("desired_state").(string); desiredState != "" {
+		return desiredState != containerStateStopped
+	}
+	return d.Get("start").(bool)
+}
+
+// containerMustRun returns whether the container is expected to be running.
+func containerMustRun(d *schema.ResourceData) bool {
+	if containerIsJob(d) {
+		return false
+	}
+	if desiredState := d.Get("desired_state").(string); desiredState != "" {
+		return desiredState != containerStateStopped
+	}
+	return d.Get("must_run").(bool)
+}
+
+// containerStateOf returns the state of the container as one of the values of desired_state.
+// A restarting container is considered running.
+func containerStateOf(state *container.State) string {
+	switch {
+	case state == nil:
+		return containerStateStopped
+	case state.Paused:
+		return containerStatePaused
+	case state.Running || state.Restarting:
+		return containerStateRunning
+	default:
+		return containerStateStopped
+	}
+}
+
+// containerDesiredStateTransitions returns the operations which change the state of the
+// container to the desired state. A paused container is unpaused before it is stopped.
+func containerDesiredStateTransitions(state *container.State, desiredState string) []string {
+	currentState := containerStateOf(state)
+	if currentState == desiredState {
+		return nil
+	}
+
+	switch desiredState {
+	case containerStateRunning:
+		if currentState == containerStatePaused {
+			return []string{"unpause"}
+		}
+		return []string{"start"}
+	case containerStatePaused:
+		if currentState == containerStateStopped {
+			return []string{"start", "pause"}
+		}
+		return []string{"pause"}
+	default:
+		if currentState == containerStatePaused {
+			return []string{"unpause", "stop"}
+		}
+		return []string{"stop"}
+	}
+}
+
+// applyContainerDesiredState changes the state of the container to the desired state, and
+// runs the exec hooks of the container when it is started or stopped.
+func applyContainerDesiredState(ctx context.Context, client *client.Client, d *schema.ResourceData, desiredState string) diag.Diagnostics {
+	containerID := d.Id()
+	inspected, err := client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return diag.Errorf("Unable to inspect container %s: %s", containerID, err)
+	}
+
+	var diags diag.Diagnostics
+	for _, transition := range containerDesiredStateTransitions(inspected.State, desiredState) {
+		log.Printf("[INFO] Changing the state of container %s to %s: %s", containerID, desiredState, transition)
+		switch transition {
+		case "start":
+			err = client.ContainerStart(ctx, containerID, container.StartOptions{})
+		case "pause":
+			err = client.ContainerPause(ctx, containerID)
+		case "unpause":
+			err = client.ContainerUnpause(ctx, containerID)
+		case "stop":
+			// the container is running, as paused containers are unpaused first
+			if diags = append(diags, runContainerExecHooks(ctx, client, d, containerPreStopExec)...); diags.HasError() {
+				return diags
 			}
-			if v, ok := d.GetOk("device_write_iops"); ok {
-				resources.BlkioDeviceWriteIOps = throttleDeviceSetToDockerThrottleDevices(v.(*schema.Set))
+			// uses the stop_timeout of the container
+			err = client.ContainerStop(ctx, containerID, container.StopOptions{})
+		}
+		if err != nil {
+			return append(diags, diag.Errorf("Unable to %s container %s: %s", transition, containerID, err)...)
+		}
+		if transition == "start" {
+			if diags = append(diags, runContainerExecHooks(ctx, client, d, containerPostStartExec)...); diags.HasError() {
+				return diags
 			}
+		}
+	}
+	return diags
+}
 
-			updateConfig := container.UpdateConfig{
-				RestartPolicy: container.RestartPolicy{
-					Name:              container.RestartPolicyMode(d.Get("restart").(string)),
-					MaximumRetryCount: d.Get("max_retry_count").(int),
-				},
-				Resources: resources,
-			}
+// containerUpdateAttributes are the attributes which are applied to the running container by
+// ContainerUpdate. The ulimits are not part of it, as the daemon ignores them on updates, see
+// https://github.com/terraform-providers/terraform-provider-docker/pull/236#discussion_r373819536
+var containerUpdateAttributes = []string{
+	"restart", "max_retry_count", "cpus", "cpu_period", "cpu_quota", "cpu_shares", "cpu_set",
+	"memory", "memory_reservation", "memory_swap", "pids_limit", "blkio_weight",
+}
 
-			if ms, ok := d.GetOk("memory_swap"); ok {
-				a := int64(ms.(int))
-				if a > 0 {
-					a = a * 1024 * 1024
-				}
-				// QF1008: could remove embedded field "Resources" from selector
-				updateConfig.Resources.MemorySwap = a //nolint:staticcheck
+// containerUpdateForceNewIfRemoved are the attributes which can be changed in place, but not
+// removed, as ContainerUpdate keeps the current value of the container for zero values.
+// memory_swap is not part of it, as the daemon defaults it to twice the memory.
+var containerUpdateForceNewIfRemoved = []string{
+	"cpus", "cpu_period", "cpu_quota", "cpu_shares", "cpu_set",
+	"memory", "memory_reservation", "blkio_weight",
+}
+
+// containerUpdateConfig returns the configuration to update the resources and the restart
+// policy of the container with.
+func containerUpdateConfig(d *schema.ResourceData) (container.UpdateConfig, error) {
+	resources := container.Resources{
+		CPUShares:         int64(d.Get("cpu_shares").(int)),
+		Memory:            int64(d.Get("memory").(int)) * 1024 * 1024,
+		MemoryReservation: int64(d.Get("memory_reservation").(int)) * 1024 * 1024,
+		CpusetCpus:        d.Get("cpu_set").(string),
+		BlkioWeight:       uint16(d.Get("blkio_weight").(int)),
+	}
+
+	if v, ok := d.GetOk("cpus"); ok {
+		nanocpus, err := opts.ParseCPUs(v.(string))
+		if err != nil {
+			return container.UpdateConfig{}, fmt.Errorf("error setting cpus: %w", err)
+		}
+		resources.NanoCPUs = nanocpus
+	} else {
+		resources.CPUPeriod = int64(d.Get("cpu_period").(int))
+		resources.CPUQuota = int64(d.Get("cpu_quota").(int))
+	}
+
+	if d.HasChange("pids_limit") {
+		// 0 removes the limit
+		pidsLimit := int64(d.Get("pids_limit").(int))
+		resources.PidsLimit = &pidsLimit
+	}
+
+	if ms, ok := d.GetOk("memory_swap"); ok {
+		a := int64(ms.(int))
+		if a > 0 {
+			a = a * 1024 * 1024
+		}
+		resources.MemorySwap = a
+	}
+
+	return container.UpdateConfig{
+		RestartPolicy: container.RestartPolicy{
+			Name:              container.RestartPolicyMode(d.Get("restart").(string)),
+			MaximumRetryCount: d.Get("max_retry_count").(int),
+		},
+		Resources: resources,
+	}, nil
+}
+
+// resourceDockerContainerCustomizeDiff validates the job and replaces the container for resource
+// changes the daemon can not apply to an existing container or to a removed job container.
+func resourceDockerContainerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := validateContainerJob(d); err != nil {
+		return err
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.HasChanges("name", "networks_advanced") {
+		if err := d.SetNewComputed("network_data"); err != nil {
+			return err
+		}
+	}
+
+	if err := diffContainerUploadHashes(ctx, d); err != nil {
+		return err
+	}
+
+	if err := diffRemovedContainerJob(d); err != nil {
+		return err
+	}
+
+	// the container would not be connected to any network anymore, as it was disconnected from
+	// the default network on creation
+	if oldNetworks, newNetworks := d.GetChange("networks_advanced"); oldNetworks.(*schema.Set).Len() > 0 && newNetworks.(*schema.Set).Len() == 0 {
+		log.Printf("[DEBUG] networks_advanced of container %s is removed, which requires a new container", d.Id())
+		if err := d.ForceNew("networks_advanced"); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range containerUpdateForceNewIfRemoved {
+		if !d.HasChange(key) {
+			continue
+		}
+		if oldValue, newValue := d.GetChange(key); containerUpdateValueRemoved(oldValue, newValue) {
+			log.Printf("[DEBUG] %s of container %s is removed, which requires a new container", key, d.Id())
+			if err := d.ForceNew(key); err != nil {
+				return err
 			}
-			_, err = client.ContainerUpdate(ctx, d.Id(), updateConfig)
-			if err != nil {
-				return diag.Errorf("Unable to update a container: %v", err)
+		}
+	}
+
+	// the daemon refuses to set cpus on a container with a CPU period or quota and vice versa
+	if oldCpus, newCpus := d.GetChange("cpus"); oldCpus.(string) == "" && newCpus.(string) != "" {
+		oldCPUPeriod, _ := d.GetChange("cpu_period")
+		oldCPUQuota, _ := d.GetChange("cpu_quota")
+		if oldCPUPeriod.(int) != 0 || oldCPUQuota.(int) != 0 {
+			log.Printf("[DEBUG] cpus of container %s replaces cpu_period and cpu_quota, which requires a new container", d.Id())
+			if err := d.ForceNew("cpus"); err != nil {
+				return err
 			}
-			break
 		}
 	}
 
 	return nil
 }
 
+// containerUpdateValueRemoved returns whether the value of an attribute is removed, i.e.
+// changed from a non-zero value to the zero value.
+func containerUpdateValueRemoved(oldValue, newValue interface{}) bool {
+	switch newValue := newValue.(type) {
+	case int:
+		return newValue == 0 && oldValue.(int) != 0
+	case string:
+		return newValue == "" && oldValue.(string) != ""
+	}
+	return false
+}
+
 func resourceDockerContainerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client, err := meta.(*ProviderConfig).MakeClient(ctx, d)
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to create Docker client: %w", err))
 	}
 
+	diags := runContainerPreStopExecHooks(ctx, client, d)
+	if diags.HasError() {
+		return diags
+	}
+
 	if !d.Get("attach").(bool) {
 		// Stop the container before removing if destroy_grace_seconds is defined
 		var timeout int
@@ -1127,7 +1473,7 @@ func resourceDockerContainerDelete(ctx context.Context, d *schema.ResourceData,
 	}
 
 	d.SetId("")
-	return nil
+	return diags
 }
 
 func fetchDockerContainer(ctx context.Context, ID string, client *client.Client) (*container.Summary, error) {
@@ -3,8 +3,11 @@ package provider
 import (
 	"bytes"
 	"context"
+	"reflect"
 	"testing"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -35,6 +38,148 @@ func TestDockerContainer_DeleteMissingContainer(t *testing.T) {
 	}
 }
 
+func TestContainerUpdateConfig(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":            "update",
+		"image":           "sha256:deadbeef",
+		"restart":         "on-failure",
+		"max_retry_count": 3,
+		"cpus":            "1.5",
+		"cpu_period":      100000,
+		"cpu_quota":       50000,
+		"memory":          512,
+		"memory_swap":     -1,
+		"pids_limit":      100,
+		"blkio_weight":    300,
+	}
+	d := schema.TestResourceDataRaw(t, resourceDockerContainer().Schema, raw)
+
+	updateConfig, err := containerUpdateConfig(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if updateConfig.RestartPolicy.Name != container.RestartPolicyOnFailure || updateConfig.RestartPolicy.MaximumRetryCount != 3 {
+		t.Fatalf("unexpected restart policy: %+v", updateConfig.RestartPolicy)
+	}
+	// cpu_period and cpu_quota are ignored if cpus is set, just like on create
+	if updateConfig.NanoCPUs != 1500000000 || updateConfig.CPUPeriod != 0 || updateConfig.CPUQuota != 0 {
+		t.Fatalf("unexpected cpu resources: nano cpus %d, period %d, quota %d", updateConfig.NanoCPUs, updateConfig.CPUPeriod, updateConfig.CPUQuota)
+	}
+	if updateConfig.Memory != 512*1024*1024 || updateConfig.MemorySwap != -1 {
+		t.Fatalf("unexpected memory resources: memory %d, swap %d", updateConfig.Memory, updateConfig.MemorySwap)
+	}
+	if updateConfig.PidsLimit == nil || *updateConfig.PidsLimit != 100 {
+		t.Fatalf("unexpected pids limit: %v", updateConfig.PidsLimit)
+	}
+	if updateConfig.BlkioWeight != 300 {
+		t.Fatalf("unexpected blkio weight: %d", updateConfig.BlkioWeight)
+	}
+
+	delete(raw, "cpus")
+	d = schema.TestResourceDataRaw(t, resourceDockerContainer().Schema, raw)
+	updateConfig, err = containerUpdateConfig(d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updateConfig.NanoCPUs != 0 || updateConfig.CPUPeriod != 100000 || updateConfig.CPUQuota != 50000 {
+		t.Fatalf("unexpected cpu resources without cpus: nano cpus %d, period %d, quota %d", updateConfig.NanoCPUs, updateConfig.CPUPeriod, updateConfig.CPUQuota)
+	}
+}
+
+func TestContainerBlkioWeightValidation(t *testing.T) {
+	validate := resourceDockerContainer().Schema["blkio_weight"].ValidateFunc
+	for _, weight := range []int{0, 10, 500, 1000} {
+		if _, errs := validate(weight, "blkio_weight"); len(errs) > 0 {
+			t.Errorf("expected %d to be valid, got %v", weight, errs)
+		}
+	}
+	for _, weight := range []int{-1, 1, 9, 1001} {
+		if _, errs := validate(weight, "blkio_weight"); len(errs) == 0 {
+			t.Errorf("expected %d to be invalid", weight)
+		}
+	}
+}
+
+func TestContainerUpdateValueRemoved(t *testing.T) {
+	cases := []struct {
+		oldValue interface{}
+		newValue interface{}
+		removed  bool
+	}{
+		{oldValue: 512, newValue: 0, removed: true},
+		{oldValue: 512, newValue: 256, removed: false},
+		{oldValue: 0, newValue: 256, removed: false},
+		{oldValue: "1.5", newValue: "", removed: true},
+		{oldValue: "1.5", newValue: "0.5", removed: false},
+		{oldValue: "", newValue: "0-1", removed: false},
+	}
+
+	for _, tc := range cases {
+		if removed := containerUpdateValueRemoved(tc.oldValue, tc.newValue); removed != tc.removed {
+			t.Errorf("expected removed to be %t for %v -> %v, got %t", tc.removed, tc.oldValue, tc.newValue, removed)
+		}
+	}
+}
+
+func TestContainerNetworksMissingDNSName(t *testing.T) {
+	inspected := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			State: &container.State{Running: true},
+		},
+		NetworkSettings: &container.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"bridge":   {},
+				"frontend": {DNSNames: []string{"new-name", "0123456789ab"}},
+				"backend":  {DNSNames: []string{"old-name", "0123456789ab"}},
+				"database": {DNSNames: []string{"old-name", "db"}},
+			},
+		},
+	}
+
+	missing := containerNetworksMissingDNSName(inspected, "new-name")
+	if !reflect.DeepEqual(missing, []string{"backend", "database"}) {
+		t.Fatalf("expected backend and database to miss the new name, got %v", missing)
+	}
+
+	inspected.State.Running = false
+	if missing := containerNetworksMissingDNSName(inspected, "new-name"); len(missing) != 0 {
+		t.Fatalf("expected no networks for a stopped container, got %v", missing)
+	}
+}
+
+func TestContainerDesiredStateTransitions(t *testing.T) {
+	running := &container.State{Running: true}
+	paused := &container.State{Running: true, Paused: true}
+	stopped := &container.State{Status: "exited"}
+
+	testCases := []struct {
+		state        *container.State
+		desiredState string
+		expected     []string
+	}{
+		{state: running, desiredState: "running"},
+		{state: running, desiredState: "paused", expected: []string{"pause"}},
+		{state: running, desiredState: "stopped", expected: []string{"stop"}},
+		{state: paused, desiredState: "running", expected: []string{"unpause"}},
+		{state: paused, desiredState: "paused"},
+		{state: paused, desiredState: "stopped", expected: []string{"unpause", "stop"}},
+		{state: stopped, desiredState: "running", expected: []string{"start"}},
+		{state: stopped, desiredState: "paused", expected: []string{"start", "pause"}},
+		{state: stopped, desiredState: "stopped"},
+		{state: &container.State{Restarting: true}, desiredState: "running"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(containerStateOf(tc.state)+" to "+tc.desiredState, func(t *testing.T) {
+			transitions := containerDesiredStateTransitions(tc.state, tc.desiredState)
+			if !reflect.DeepEqual(transitions, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, transitions)
+			}
+		})
+	}
+}
+
 func TestCopyContainerLogs_Demultiplex(t *testing.T) {
 	var input bytes.Buffer
 	stdoutWriter := stdcopy.NewStdWriter(&input, stdcopy.Stdout)
@@ -69,3 +214,26 @@ func TestCopyContainerLogs_TTY(t *testing.T) {
 		t.Fatalf("unexpected logs output: got %q, want %q", got, want)
 	}
 }
+
+func TestContainerNetworkModeNetwork(t *testing.T) {
+	cases := []struct {
+		networkMode string
+		network     string
+		connected   bool
+	}{
+		{networkMode: "", network: "bridge", connected: true},
+		{networkMode: "default", network: "bridge", connected: true},
+		{networkMode: "bridge", network: "bridge", connected: true},
+		{networkMode: "backend", network: "backend", connected: true},
+		{networkMode: "host", connected: false},
+		{networkMode: "none", connected: false},
+		{networkMode: "container:db", connected: false},
+	}
+
+	for _, tc := range cases {
+		networkName, connected := containerNetworkModeNetwork(container.NetworkMode(tc.networkMode))
+		if networkName != tc.network || connected != tc.connected {
+			t.Errorf("expected %q, %t for network mode %q, got %q, %t", tc.network, tc.connected, tc.networkMode, networkName, connected)
+		}
+	}
+}
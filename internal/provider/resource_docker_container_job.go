@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// containerJob is a container which is run to completion. It is attached to and waited for
+// before it is started, so neither its logs nor its exit code are lost if it is removed
+// right after it exited.
+type containerJob struct {
+	containerID string
+	timeout     time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	waitC  <-chan container.WaitResponse
+	errC   <-chan error
+
+	attach   types.HijackedResponse
+	logs     bytes.Buffer
+	logsDone chan error
+}
+
+// containerIsJob returns whether the container is run in job mode.
+func containerIsJob(d *schema.ResourceData) bool {
+	job := d.Get("job").([]interface{})
+	return len(job) > 0
+}
+
+// validateContainerJob rejects a job which is not started on creation, as it would never run.
+func validateContainerJob(d *schema.ResourceDiff) error {
+	if len(d.Get("job").([]interface{})) == 0 || !d.NewValueKnown("start") || !d.NewValueKnown("desired_state") {
+		return nil
+	}
+	return containerJobStartConflict(d.Get("start").(bool), d.Get("desired_state").(string))
+}
+
+// containerJobStartConflict returns an error if a container with the given start and
+// desired_state is not started on creation. desired_state takes precedence over start.
+func containerJobStartConflict(start bool, desiredState string) error {
+	switch {
+	case desiredState != "" && desiredState != containerStateRunning:
+		return fmt.Errorf("job can not be used together with desired_state %q, as the job would never run", desiredState)
+	case desiredState == "" && !start:
+		return fmt.Errorf("job can not be used together with start = false, as the job would never run")
+	}
+	return nil
+}
+
+// containerJobInPlaceAttributes are the attributes which are otherwise changed on the
+// existing container by Update.
+var containerJobInPlaceAttributes = append([]string{
+	"name", "must_run", "desired_state", "upload", "upload_sha256", "networks_advanced",
+}, containerUpdateAttributes...)
+
+// diffRemovedContainerJob replaces the container of a job with rm for every change which is
+// otherwise applied in place, as the daemon removed the container after the job completed.
+func diffRemovedContainerJob(d *schema.ResourceDiff) error {
+	oldJob, _ := d.GetChange("job")
+	oldRm, _ := d.GetChange("rm")
+	if len(oldJob.([]interface{})) == 0 || !oldRm.(bool) {
+		return nil
+	}
+
+	for _, key := range containerJobInPlaceAttributes {
+		if !d.HasChange(key) {
+			continue
+		}
+		log.Printf("[DEBUG] %s of the removed container %s of the job changed, which requires a new container", key, d.Id())
+		if err := d.ForceNew(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containerJobSuccessExitCodes returns the exit codes the job is successful with.
+func containerJobSuccessExitCodes(d *schema.ResourceData) []int {
+	job := d.Get("job").([]interface{})
+	if len(job) == 0 || job[0] == nil {
+		return []int{0}
+	}
+
+	exitCodes := []int{}
+	for _, exitCode := range job[0].(map[string]interface{})["success_exit_codes"].(*schema.Set).List() {
+		exitCodes = append(exitCodes, exitCode.(int))
+	}
+	if len(exitCodes) == 0 {
+		return []int{0}
+	}
+	slices.Sort(exitCodes)
+	return exitCodes
+}
+
+// attachContainerJob attaches to the created container of the job, which has to be started
+// afterwards.
+func attachContainerJob(ctx context.Context, client *client.Client, d *schema.ResourceData) (*containerJob, error) {
+	job := &containerJob{containerID: d.Id()}
+	if rawJob := d.Get("job").([]interface{}); rawJob[0] != nil {
+		// the timeout is validated by the schema
+		job.timeout, _ = time.ParseDuration(rawJob[0].(map[string]interface{})["timeout"].(string))
+	}
+
+	attach, err := client.ContainerAttach(ctx, job.containerID, container.AttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+		Logs:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to attach to the container of the job: %w", err)
+	}
+	job.attach = attach
+
+	job.logsDone = make(chan error, 1)
+	go func() {
+		job.logsDone <- copyContainerLogs(&job.logs, attach.Reader, d.Get("tty").(bool))
+	}()
+
+	if job.timeout > 0 {
+		job.ctx, job.cancel = context.WithTimeout(ctx, job.timeout)
+	} else {
+		job.ctx, job.cancel = context.WithCancel(ctx)
+	}
+
+	// waiting for the removal makes sure the container is cleaned up when the job is done
+	waitCondition := container.WaitConditionNextExit
+	if d.Get("rm").(bool) {
+		waitCondition = container.WaitConditionRemoved
+	}
+	job.waitC, job.errC = client.ContainerWait(job.ctx, job.containerID, waitCondition)
+
+	return job, nil
+}
+
+// wait waits for the job to exit and returns its exit code. The container is killed if the
+// job does not exit within its timeout.
+func (job *containerJob) wait(ctx context.Context, client *client.Client) (int64, error) {
+	var exitCode int64
+	select {
+	case response := <-job.waitC:
+		if response.Error != nil && response.Error.Message != "" {
+			return 0, fmt.Errorf("unable to wait for the job: %s", response.Error.Message)
+		}
+		exitCode = response.StatusCode
+	case err := <-job.errC:
+		if !errors.Is(job.ctx.Err(), context.DeadlineExceeded) {
+			return 0, fmt.Errorf("unable to wait for the job: %w", err)
+		}
+
+		log.Printf("[INFO] Killing container %s of the job after its timeout of %s", job.containerID, job.timeout)
+		if err := client.ContainerKill(ctx, job.containerID, "KILL"); err != nil {
+			if !containsIgnorableErrorMessage(err.Error(), "No such container", "is not running") {
+				return 0, fmt.Errorf("unable to kill the job after its timeout of %s: %w", job.timeout, err)
+			}
+		}
+		<-job.logsDone
+		return 0, fmt.Errorf("the job did not complete within %s", job.timeout)
+	}
+
+	if err := <-job.logsDone; err != nil {
+		log.Printf("[WARN] Unable to read all logs of container %s of the job: %s", job.containerID, err)
+	}
+	return exitCode, nil
+}
+
+func (job *containerJob) close() {
+	job.cancel()
+	job.attach.Close()
+}
+
+// runContainerJob waits for the started job, and fails if the job does not exit with one of
+// its successful exit codes.
+func runContainerJob(ctx context.Context, client *client.Client, d *schema.ResourceData, job *containerJob) diag.Diagnostics {
+	exitCode, err := job.wait(ctx, client)
+	logs := job.logs.String()
+	if d.Get("logs").(bool) {
+		d.Set("container_logs", logs)
+	}
+
+	var summary, detail string
+	if err != nil {
+		summary = fmt.Sprintf("Container job %s failed", job.containerID)
+		detail = err.Error()
+	} else {
+		d.Set("exit_code", exitCode)
+		successExitCodes := containerJobSuccessExitCodes(d)
+		if slices.Contains(successExitCodes, int(exitCode)) {
+			log.Printf("[INFO] Container %s of the job exited with code %d", job.containerID, exitCode)
+			return nil
+		}
+		summary = fmt.Sprintf("Container job %s failed with exit code %d", job.containerID, exitCode)
+		detail = fmt.Sprintf("The successful exit codes of the job are %v.", successExitCodes)
+	}
+
+	if tail := lastLogLines(logs, containerDiagnosticLogLines); tail != "" {
+		detail += fmt.Sprintf("\n\nLast %d log lines of the container:\n%s", containerDiagnosticLogLines, tail)
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  summary,
+		Detail:   detail,
+	}}
+}
+
+// lastLogLines returns the last n lines of the logs.
+func lastLogLines(logs string, n int) string {
+	lines := strings.Split(strings.TrimRight(logs, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
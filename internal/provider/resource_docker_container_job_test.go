@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestContainerJobSuccessExitCodes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      map[string]interface{}
+		isJob    bool
+		expected []int
+	}{
+		{
+			name:     "no job",
+			raw:      map[string]interface{}{},
+			expected: []int{0},
+		},
+		{
+			name: "default",
+			raw: map[string]interface{}{
+				"job": []interface{}{map[string]interface{}{"timeout": "5m"}},
+			},
+			isJob:    true,
+			expected: []int{0},
+		},
+		{
+			name: "configured",
+			raw: map[string]interface{}{
+				"job": []interface{}{map[string]interface{}{"success_exit_codes": []interface{}{3, 0}}},
+			},
+			isJob:    true,
+			expected: []int{0, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.raw["name"] = "job"
+			tc.raw["image"] = "busybox"
+			d := schema.TestResourceDataRaw(t, resourceDockerContainer().Schema, tc.raw)
+
+			if isJob := containerIsJob(d); isJob != tc.isJob {
+				t.Fatalf("expected job mode to be %t, got %t", tc.isJob, isJob)
+			}
+			if exitCodes := containerJobSuccessExitCodes(d); !reflect.DeepEqual(exitCodes, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, exitCodes)
+			}
+		})
+	}
+}
+
+func TestContainerJobStartConflict(t *testing.T) {
+	cases := []struct {
+		start        bool
+		desiredState string
+		conflict     bool
+	}{
+		{start: true, desiredState: "", conflict: false},
+		{start: false, desiredState: "", conflict: true},
+		{start: true, desiredState: containerStateRunning, conflict: false},
+		{start: false, desiredState: containerStateRunning, conflict: false},
+		{start: true, desiredState: containerStateStopped, conflict: true},
+		{start: true, desiredState: containerStatePaused, conflict: true},
+	}
+
+	for _, tc := range cases {
+		err := containerJobStartConflict(tc.start, tc.desiredState)
+		if conflict := err != nil; conflict != tc.conflict {
+			t.Errorf("expected conflict to be %t for start %t and desired_state %q, got %v", tc.conflict, tc.start, tc.desiredState, err)
+		}
+	}
+}
+
+func TestLastLogLines(t *testing.T) {
+	var logs strings.Builder
+	for _, line := range []string{"one", "two", "three", "four"} {
+		logs.WriteString(line + "\n")
+	}
+
+	if tail := lastLogLines(logs.String(), 2); tail != "three\nfour" {
+		t.Fatalf("expected the last 2 lines, got %q", tail)
+	}
+	if tail := lastLogLines(logs.String(), 10); tail != "one\ntwo\nthree\nfour" {
+		t.Fatalf("expected all lines, got %q", tail)
+	}
+	if tail := lastLogLines("", 10); tail != "" {
+		t.Fatalf("expected no lines, got %q", tail)
+	}
+}
@@ -0,0 +1,307 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// containerDiagnosticLogLines is the number of log lines of the container which are reported
+// if it does not become ready or its job fails.
+const containerDiagnosticLogLines = 20
+
+// containerTCPReadinessReadTimeout is the time the tcp readiness probe waits for the connection
+// to be closed by the peer after it was accepted.
+const containerTCPReadinessReadTimeout = 500 * time.Millisecond
+
+// containerReadinessCheck checks the readiness of a container once. The log probe follows the
+// logs until ctx is done though.
+type containerReadinessCheck func(ctx context.Context) error
+
+// waitForContainerReadiness runs the configured probes of the readiness block one after another.
+func waitForContainerReadiness(ctx context.Context, client *client.Client, d *schema.ResourceData) diag.Diagnostics {
+	rawReadiness := d.Get("readiness").([]interface{})
+	if len(rawReadiness) == 0 || rawReadiness[0] == nil {
+		return nil
+	}
+	readiness := rawReadiness[0].(map[string]interface{})
+	tty := d.Get("tty").(bool)
+
+	for _, probe := range []string{"log", "tcp", "http", "exec"} {
+		rawProbe := readiness[probe].([]interface{})
+		if len(rawProbe) == 0 || rawProbe[0] == nil {
+			continue
+		}
+		config := rawProbe[0].(map[string]interface{})
+
+		// the durations are validated by the schema
+		timeout, _ := time.ParseDuration(config["timeout"].(string))
+		interval, _ := time.ParseDuration(config["interval"].(string))
+
+		var check containerReadinessCheck
+		switch probe {
+		case "log":
+			pattern := regexp.MustCompile(config["pattern"].(string))
+			check = containerLogReadinessCheck(client, d.Id(), tty, pattern)
+		case "tcp":
+			check = containerTCPReadinessCheck(client, d.Id(), config)
+		case "http":
+			check = containerHTTPReadinessCheck(client, d.Id(), config)
+		case "exec":
+			check = containerExecReadinessCheck(client, d.Id(), stringListToStringSlice(config["command"].([]interface{})))
+		}
+
+		log.Printf("[DEBUG] Waiting for the %s readiness probe of container %s", probe, d.Id())
+		if err := pollContainerReadiness(ctx, timeout, interval, check); err != nil {
+			detail := fmt.Sprintf("The %s readiness probe did not succeed within %s: %s", probe, timeout, err)
+			if logs, err := containerLastLogLines(ctx, client, d.Id(), tty); err != nil {
+				log.Printf("[WARN] Unable to read the logs of container %s: %s", d.Id(), err)
+			} else if logs != "" {
+				detail += fmt.Sprintf("\n\nLast %d log lines of the container:\n%s", containerDiagnosticLogLines, logs)
+			}
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Container %s is not ready", d.Id()),
+				Detail:   detail,
+			}}
+		}
+		log.Printf("[DEBUG] The %s readiness probe of container %s succeeded", probe, d.Id())
+	}
+
+	return nil
+}
+
+// pollContainerReadiness runs check every interval until it succeeds or the timeout expires,
+// in which case the error of the last check is returned.
+func pollContainerReadiness(ctx context.Context, timeout, interval time.Duration, check containerReadinessCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+			log.Printf("[DEBUG] Container is not ready yet: %s", err)
+		}
+	}
+}
+
+func containerLogReadinessCheck(client *client.Client, containerID string, tty bool, pattern *regexp.Regexp) containerReadinessCheck {
+	return func(ctx context.Context) error {
+		reader, err := client.ContainerLogs(ctx, containerID, container.LogsOptions{
+			ShowStdout: true,
+			ShowStderr: true,
+			Follow:     true,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to read container logs: %w", err)
+		}
+		defer reader.Close() //nolint:errcheck
+
+		matched, err := containerLogLineMatches(reader, tty, pattern)
+		if err != nil {
+			return fmt.Errorf("unable to read container logs: %w", err)
+		}
+		if !matched {
+			return fmt.Errorf("no line of the logs matches %q", pattern)
+		}
+		return nil
+	}
+}
+
+// containerLogLineMatches returns whether a line of the logs matches the pattern. It returns
+// as soon as a line matches, or otherwise when the logs end.
+func containerLogLineMatches(reader io.Reader, tty bool, pattern *regexp.Regexp) (bool, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeReader.Close() //nolint:errcheck
+	go func() {
+		pipeWriter.CloseWithError(copyContainerLogs(pipeWriter, reader, tty)) //nolint:errcheck
+	}()
+
+	scanner := bufio.NewScanner(pipeReader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if pattern.MatchString(scanner.Text()) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+func containerTCPReadinessCheck(client *client.Client, containerID string, config map[string]interface{}) containerReadinessCheck {
+	return func(ctx context.Context) error {
+		address, err := containerReadinessAddress(ctx, client, containerID, config)
+		if err != nil {
+			return err
+		}
+
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		defer conn.Close() //nolint:errcheck
+
+		return checkTCPConnectionOpen(conn, containerTCPReadinessReadTimeout)
+	}
+}
+
+// checkTCPConnectionOpen returns an error if the peer closes the connection within the
+// timeout. The userland proxy of Docker accepts connections to published ports before the
+// process in the container listens, and closes them right away in that case.
+func checkTCPConnectionOpen(conn net.Conn, timeout time.Duration) error {
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	_, err := conn.Read(make([]byte, 1))
+	var netErr net.Error
+	switch {
+	case err == nil:
+		// the server sent a greeting
+		return nil
+	case errors.As(err, &netErr) && netErr.Timeout():
+		// the server waits for the client to send something
+		return nil
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("connection to %s was closed right after it was accepted", conn.RemoteAddr())
+	default:
+		return err
+	}
+}
+
+func containerHTTPReadinessCheck(client *client.Client, containerID string, config map[string]interface{}) containerReadinessCheck {
+	return func(ctx context.Context) error {
+		address, err := containerReadinessAddress(ctx, client, containerID, config)
+		if err != nil {
+			return err
+		}
+
+		path := config["path"].(string)
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		requestURL := fmt.Sprintf("%s://%s%s", config["scheme"].(string), address, path)
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+		if err != nil {
+			return err
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close() //nolint:errcheck
+		_, _ = io.Copy(io.Discard, response.Body)
+
+		if expected := config["expected_status"].(int); response.StatusCode != expected {
+			return fmt.Errorf("GET %s returned status %d instead of %d", requestURL, response.StatusCode, expected)
+		}
+		return nil
+	}
+}
+
+func containerExecReadinessCheck(client *client.Client, containerID string, command []string) containerReadinessCheck {
+	return func(ctx context.Context) error {
+		execCreateResponse, err := client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+			AttachStdout: true,
+			AttachStderr: true,
+			Cmd:          command,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to create exec: %w", err)
+		}
+
+		result, err := runContainerExec(ctx, client, execCreateResponse.ID, false)
+		if err != nil {
+			return err
+		}
+		if result.exitCode != 0 {
+			output := result.stdout.String() + result.stderr.String()
+			return fmt.Errorf("%q exited with code %d: %s", strings.Join(command, " "), result.exitCode, strings.TrimSpace(output))
+		}
+		return nil
+	}
+}
+
+// containerReadinessAddress returns the address the container port of the probe is published
+// on, as ports are only published once the container is running.
+func containerReadinessAddress(ctx context.Context, client *client.Client, containerID string, config map[string]interface{}) (string, error) {
+	inspected, err := client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", fmt.Errorf("unable to inspect container: %w", err)
+	}
+	if inspected.NetworkSettings == nil {
+		return "", fmt.Errorf("container has no network settings")
+	}
+	return publishedPortAddress(inspected.NetworkSettings.Ports, config["port"].(int), config["host"].(string), client.DaemonHost())
+}
+
+// publishedPortAddress returns the address to connect to the published TCP port of the
+// container port with. Ports published on all interfaces are connected to on the host of
+// the Docker daemon, or localhost if the daemon is reached by a socket.
+func publishedPortAddress(ports nat.PortMap, port int, host, daemonHost string) (string, error) {
+	for _, binding := range ports[nat.Port(fmt.Sprintf("%d/tcp", port))] {
+		if binding.HostPort == "" {
+			continue
+		}
+
+		if host == "" {
+			host = binding.HostIP
+		}
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			host = "localhost"
+			if daemonURL, err := url.Parse(daemonHost); err == nil && daemonURL.Hostname() != "" {
+				switch daemonURL.Scheme {
+				case "tcp", "ssh", "http", "https":
+					host = daemonURL.Hostname()
+				}
+			}
+		}
+		return net.JoinHostPort(host, binding.HostPort), nil
+	}
+
+	return "", fmt.Errorf("container port %d/tcp is not published", port)
+}
+
+// containerLastLogLines returns the last lines of the container logs.
+func containerLastLogLines(ctx context.Context, client *client.Client, containerID string, tty bool) (string, error) {
+	reader, err := client.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       strconv.Itoa(containerDiagnosticLogLines),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	var logs bytes.Buffer
+	if err := copyContainerLogs(&logs, reader, tty); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(logs.String(), "\n"), nil
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+)
+
+func TestContainerLogLineMatches(t *testing.T) {
+	var multiplexed bytes.Buffer
+	if _, err := stdcopy.NewStdWriter(&multiplexed, stdcopy.Stdout).Write([]byte("starting\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdcopy.NewStdWriter(&multiplexed, stdcopy.Stderr).Write([]byte("database system is ready to accept connections\n")); err != nil {
+		t.Fatal(err)
+	}
+	logs := multiplexed.Bytes()
+
+	pattern := regexp.MustCompile(`ready to accept connections$`)
+	matched, err := containerLogLineMatches(bytes.NewReader(logs), false, pattern)
+	if err != nil || !matched {
+		t.Fatalf("expected the multiplexed logs to match, got %t: %v", matched, err)
+	}
+
+	matched, err = containerLogLineMatches(strings.NewReader("starting\r\nready to accept connections\r\n"), true, regexp.MustCompile(`connections\r?$`))
+	if err != nil || !matched {
+		t.Fatalf("expected the tty logs to match, got %t: %v", matched, err)
+	}
+
+	matched, err = containerLogLineMatches(bytes.NewReader(logs), false, regexp.MustCompile(`^listening`))
+	if err != nil || matched {
+		t.Fatalf("expected the logs not to match, got %t: %v", matched, err)
+	}
+}
+
+func TestPublishedPortAddress(t *testing.T) {
+	ports := nat.PortMap{
+		"80/tcp":   {{HostIP: "0.0.0.0", HostPort: "32768"}, {HostIP: "::", HostPort: "32768"}},
+		"443/tcp":  {{HostIP: "127.0.0.1", HostPort: "8443"}},
+		"5432/udp": {{HostIP: "0.0.0.0", HostPort: "5432"}},
+	}
+
+	testCases := []struct {
+		name       string
+		port       int
+		host       string
+		daemonHost string
+		expected   string
+	}{
+		{name: "socket", port: 80, daemonHost: "unix:///var/run/docker.sock", expected: "localhost:32768"},
+		{name: "tcp daemon", port: 80, daemonHost: "tcp://docker.example.com:2376", expected: "docker.example.com:32768"},
+		{name: "ssh daemon", port: 80, daemonHost: "ssh://user@10.0.0.5", expected: "10.0.0.5:32768"},
+		{name: "published on an interface", port: 443, daemonHost: "tcp://docker.example.com:2376", expected: "127.0.0.1:8443"},
+		{name: "configured host", port: 80, host: "app.internal", daemonHost: "unix:///var/run/docker.sock", expected: "app.internal:32768"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			address, err := publishedPortAddress(ports, tc.port, tc.host, tc.daemonHost)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if address != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, address)
+			}
+		})
+	}
+
+	if _, err := publishedPortAddress(ports, 5432, "", "unix:///var/run/docker.sock"); err == nil {
+		t.Fatalf("expected an error for a port which is only published for udp")
+	}
+}
+
+func TestPollContainerReadiness(t *testing.T) {
+	attempts := 0
+	err := pollContainerReadiness(context.Background(), time.Second, time.Millisecond, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected success after 3 attempts, got %d attempts: %v", attempts, err)
+	}
+
+	err = pollContainerReadiness(context.Background(), 50*time.Millisecond, 10*time.Millisecond, func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	if err == nil || err.Error() != "connection refused" {
+		t.Fatalf("expected the error of the last attempt, got %v", err)
+	}
+}
+
+func TestCheckTCPConnectionOpen(t *testing.T) {
+	cases := []struct {
+		name   string
+		handle func(conn net.Conn)
+		open   bool
+	}{
+		{name: "closed", handle: func(conn net.Conn) { conn.Close() }, open: false},
+		{name: "waiting", handle: func(conn net.Conn) { time.Sleep(time.Second); conn.Close() }, open: true},
+		{name: "greeting", handle: func(conn net.Conn) { conn.Write([]byte("220 ready\r\n")); conn.Close() }, open: true}, //nolint:errcheck
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Unable to listen: %v", err)
+			}
+			defer listener.Close() //nolint:errcheck
+			go func() {
+				conn, err := listener.Accept()
+				if err == nil {
+					tc.handle(conn)
+				}
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			if err != nil {
+				t.Fatalf("Unable to connect: %v", err)
+			}
+			defer conn.Close() //nolint:errcheck
+
+			err = checkTCPConnectionOpen(conn, 200*time.Millisecond)
+			if tc.open && err != nil {
+				t.Errorf("Expected the connection to be open, got %v", err)
+			}
+			if !tc.open && err == nil {
+				t.Errorf("Expected an error for a closed connection")
+			}
+		})
+	}
+}
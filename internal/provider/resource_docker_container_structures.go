@@ -128,6 +128,37 @@ func flattenContainerNetworksAdvanced(in map[string]*network.EndpointSettings) *
 	return schema.NewSet(f, out)
 }
 
+// networkAdvancedToEndpointSettings returns the settings to connect the container to a network
+// of networks_advanced with.
+func networkAdvancedToEndpointSettings(rawNetwork map[string]interface{}) *network.EndpointSettings {
+	endpointConfig := &network.EndpointSettings{}
+	endpointIPAMConfig := &network.EndpointIPAMConfig{}
+	if v, ok := rawNetwork["aliases"]; ok {
+		endpointConfig.Aliases = stringSetToStringSlice(v.(*schema.Set))
+	}
+	if v, ok := rawNetwork["ipv4_address"]; ok {
+		endpointIPAMConfig.IPv4Address = v.(string)
+	}
+	if v, ok := rawNetwork["ipv6_address"]; ok {
+		endpointIPAMConfig.IPv6Address = v.(string)
+	}
+	if v, ok := rawNetwork["link_local_ips"]; ok {
+		endpointIPAMConfig.LinkLocalIPs = stringSetToStringSlice(v.(*schema.Set))
+	}
+	endpointConfig.IPAMConfig = endpointIPAMConfig
+
+	if v, ok := rawNetwork["mac_address"]; ok {
+		endpointConfig.MacAddress = v.(string)
+	}
+	if v, ok := rawNetwork["driver_opts"]; ok {
+		endpointConfig.DriverOpts = stringSetToMapStringString(v.(*schema.Set))
+	}
+	if v, ok := rawNetwork["gw_priority"]; ok {
+		endpointConfig.GwPriority = v.(int)
+	}
+	return endpointConfig
+}
+
 func throttleDeviceSetToDockerThrottleDevices(in *schema.Set) []*blkiodev.ThrottleDevice {
 	if in == nil || in.Len() == 0 {
 		return nil
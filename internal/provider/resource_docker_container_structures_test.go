@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -218,3 +219,30 @@ func TestFlattenDevices(t *testing.T) {
 		}
 	})
 }
+
+func TestNetworkAdvancedToEndpointSettings(t *testing.T) {
+	endpointSettings := networkAdvancedToEndpointSettings(map[string]interface{}{
+		"name":           "backend",
+		"aliases":        schema.NewSet(schema.HashString, []interface{}{"api"}),
+		"ipv4_address":   "10.0.0.2",
+		"ipv6_address":   "",
+		"link_local_ips": schema.NewSet(schema.HashString, []interface{}{"169.254.0.2"}),
+		"mac_address":    "02:42:ac:11:00:02",
+		"driver_opts":    schema.NewSet(schema.HashString, []interface{}{"com.docker.network.endpoint.ifname=eth1"}),
+		"gw_priority":    10,
+	})
+
+	expected := &network.EndpointSettings{
+		Aliases: []string{"api"},
+		IPAMConfig: &network.EndpointIPAMConfig{
+			IPv4Address:  "10.0.0.2",
+			LinkLocalIPs: []string{"169.254.0.2"},
+		},
+		MacAddress: "02:42:ac:11:00:02",
+		DriverOpts: map[string]string{"com.docker.network.endpoint.ifname": "eth1"},
+		GwPriority: 10,
+	}
+	if !reflect.DeepEqual(endpointSettings, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, endpointSettings)
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -206,6 +208,81 @@ func TestAccDockerContainer_2networks_withmode(t *testing.T) {
 	})
 }
 
+func TestAccDockerContainer_updateNetworksInPlace(t *testing.T) {
+	var c container.InspectResponse
+	var containerID string
+	resourceName := "docker_container.foo"
+
+	testCheckNetworks := func(expected map[string][]string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if len(c.NetworkSettings.Networks) != len(expected) {
+				return fmt.Errorf("expected the container to be connected to %d networks, got %v", len(expected), c.NetworkSettings.Networks)
+			}
+			for networkName, aliases := range expected {
+				endpoint, ok := c.NetworkSettings.Networks[networkName]
+				if !ok {
+					return fmt.Errorf("expected the container to be connected to network %s", networkName)
+				}
+				for _, alias := range aliases {
+					if !slices.Contains(endpoint.Aliases, alias) {
+						return fmt.Errorf("expected alias %s in network %s, got %v", alias, networkName, endpoint.Aliases)
+					}
+				}
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerUpdateNetworksConfig"), `
+  networks_advanced {
+    name    = docker_network.test_network_1.name
+    aliases = ["tftest-one"]
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceID(resourceName, &containerID),
+					testCheckNetworks(map[string][]string{"tftest-update-1": {"tftest-one"}}),
+					resource.TestCheckResourceAttr(resourceName, "network_data.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerUpdateNetworksConfig"), `
+  networks_advanced {
+    name    = docker_network.test_network_1.name
+    aliases = ["tftest-two"]
+  }
+  networks_advanced {
+    name = docker_network.test_network_2.name
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckNetworks(map[string][]string{"tftest-update-1": {"tftest-two"}, "tftest-update-2": nil}),
+					resource.TestCheckResourceAttr(resourceName, "network_data.#", "2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerUpdateNetworksConfig"), `
+  networks_advanced {
+    name = docker_network.test_network_2.name
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckNetworks(map[string][]string{"tftest-update-2": nil}),
+					resource.TestCheckResourceAttr(resourceName, "network_data.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "network_data.0.network_name", "tftest-update-2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccDockerContainer_volume(t *testing.T) {
 	var c container.InspectResponse
 
@@ -852,8 +929,8 @@ func TestAccDockerContainer_uploadSourceHash(t *testing.T) {
 		return nil
 	}
 	testCheck := func(*terraform.State) error {
-		if c.ID == firstRunId {
-			return fmt.Errorf("Container should have been recreated due to changed hash")
+		if c.ID != firstRunId {
+			return fmt.Errorf("Container should have been updated in place due to changed hash")
 		}
 		return nil
 	}
@@ -884,6 +961,166 @@ func TestAccDockerContainer_uploadSourceHash(t *testing.T) {
 	})
 }
 
+func TestAccDockerContainer_uploadInPlace(t *testing.T) {
+	var c container.InspectResponse
+	var containerID string
+	ctx := context.Background()
+	resourceName := "docker_container.foo"
+
+	readFile := func(path string) (string, error) {
+		client, err := testAccProvider.Meta().(*ProviderConfig).MakeClient(ctx, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create Docker client: %w", err)
+		}
+		r, _, err := client.CopyFromContainer(ctx, containerID, path)
+		if err != nil {
+			return "", err
+		}
+		defer r.Close() //nolint:errcheck
+
+		tr := tar.NewReader(r)
+		if _, err := tr.Next(); err != nil {
+			return "", err
+		}
+		content, err := io.ReadAll(tr)
+		return string(content), err
+	}
+
+	testCheckReloaded := func(expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if content, err := readFile("/terraform/config"); err != nil || content != expected {
+				return fmt.Errorf("expected the uploaded file to be %q, got %q: %v", expected, content, err)
+			}
+			// the signal is handled once the sleep of the container is done
+			var content string
+			var err error
+			for i := 0; i < 10; i++ {
+				if content, err = readFile("/tmp/reloaded"); err == nil && content == expected {
+					return nil
+				}
+				time.Sleep(500 * time.Millisecond)
+			}
+			return fmt.Errorf("expected the container to be signaled to reload %q, got %q: %v", expected, content, err)
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerUploadInPlaceConfig"), "v1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceID(resourceName, &containerID),
+				),
+			},
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerUploadInPlaceConfig"), "v2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckReloaded("v2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDockerContainer_uploadDirectory(t *testing.T) {
+	var c container.InspectResponse
+	var containerID string
+	ctx := context.Background()
+	resourceName := "docker_container.foo"
+
+	sourceDir := t.TempDir()
+	for name, content := range map[string]string{"app.conf": "v1", "app.conf.bak": "v0", "conf.d/extra.conf": "extra"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(sourceDir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(sourceDir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	if err := tw.WriteHeader(&tar.Header{Name: "data.txt", Mode: 0o600, Size: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sourceArchive := filepath.Join(t.TempDir(), "data.tar")
+	if err := os.WriteFile(sourceArchive, archive.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCheckFile := func(path, expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			client, err := testAccProvider.Meta().(*ProviderConfig).MakeClient(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("failed to create Docker client: %w", err)
+			}
+			r, _, err := client.CopyFromContainer(ctx, containerID, path)
+			if err != nil {
+				if expected == "" && strings.Contains(err.Error(), "Could not find the file") {
+					return nil
+				}
+				return fmt.Errorf("unable to download %s from the container: %w", path, err)
+			}
+			defer r.Close() //nolint:errcheck
+
+			tr := tar.NewReader(r)
+			if _, err := tr.Next(); err != nil {
+				return err
+			}
+			content, err := io.ReadAll(tr)
+			if err != nil || string(content) != expected {
+				return fmt.Errorf("expected %s to be %q, got %q: %v", path, expected, content, err)
+			}
+			return nil
+		}
+	}
+
+	config := fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerUploadDirectoryConfig"), sourceDir, sourceArchive)
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceID(resourceName, &containerID),
+					resource.TestCheckResourceAttrSet(resourceName, "upload_sha256./terraform/config"),
+					resource.TestCheckResourceAttrSet(resourceName, "upload_sha256./terraform/archive"),
+					testCheckFile("/terraform/config/app.conf", "v1"),
+					testCheckFile("/terraform/config/conf.d/extra.conf", "extra"),
+					testCheckFile("/terraform/config/app.conf.bak", ""),
+					testCheckFile("/terraform/archive/data.txt", "data"),
+				),
+			},
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(filepath.Join(sourceDir, "app.conf"), []byte("v2"), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckFile("/terraform/config/app.conf", "v2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccDockerContainer_uploadAsBase64(t *testing.T) {
 	var c container.InspectResponse
 	ctx := context.Background()
@@ -1458,6 +1695,242 @@ func TestAccDockerContainer_wait(t *testing.T) {
 	})
 }
 
+func TestAccDockerContainer_readiness(t *testing.T) {
+	var c container.InspectResponse
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerReadinessConfig"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning("docker_container.foo", &c),
+					resource.TestCheckResourceAttr("docker_container.foo", "readiness.0.http.0.path", "/index.html"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDockerContainer_readinessFailed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerReadinessFailedConfig"),
+				ExpectError: regexp.MustCompile(`(?s)is not ready.*no line of the logs matches.*still starting`),
+			},
+		},
+	})
+}
+
+func TestAccDockerContainer_execHooks(t *testing.T) {
+	var containerID string
+	ctx := context.Background()
+	resourceName := "docker_container.foo"
+	config := func(desiredState string) string {
+		return fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerExecHooksConfig"), desiredState)
+	}
+
+	testCheckFile := func(path, expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			client, err := testAccProvider.Meta().(*ProviderConfig).MakeClient(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("failed to create Docker client: %w", err)
+			}
+			r, _, err := client.CopyFromContainer(ctx, containerID, path)
+			if err != nil {
+				return fmt.Errorf("unable to download %s from the container: %w", path, err)
+			}
+			defer r.Close() //nolint:errcheck
+
+			tr := tar.NewReader(r)
+			if _, err := tr.Next(); err != nil {
+				return err
+			}
+			content, err := io.ReadAll(tr)
+			if err != nil || string(content) != expected {
+				return fmt.Errorf("expected %s to be %q, got %q: %v", path, expected, content, err)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("running"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceID(resourceName, &containerID),
+					testCheckFile("/tmp/post_start", "hello from /tmp\n"),
+				),
+			},
+			{
+				Config: config("stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckFile("/tmp/pre_stop", ""),
+				),
+			},
+			{
+				Config: config("running"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckFile("/tmp/post_start", "hello from /tmp\nhello from /tmp\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDockerContainer_execHooksFailed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerExecHooksFailedConfig"),
+				ExpectError: regexp.MustCompile(`(?s)post_start_exec.0.*failed.*exited with code 1.*bootstrap failed`),
+			},
+		},
+	})
+}
+
+func TestAccDockerContainer_job(t *testing.T) {
+	var containerID string
+	resourceName := "docker_container.foo"
+	config := func(exitCode, rm, timeout, migration string) string {
+		return fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerJobConfig"), exitCode, rm, timeout, migration)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("3", "true", "1m", "v1"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceID(resourceName, &containerID),
+					resource.TestCheckResourceAttr(resourceName, "exit_code", "3"),
+					resource.TestCheckResourceAttr(resourceName, "container_logs", "migrating\nmigrated\n"),
+					func(*terraform.State) error {
+						ctx := context.Background()
+						client, err := testAccProvider.Meta().(*ProviderConfig).MakeClient(ctx, nil)
+						if err != nil {
+							return err
+						}
+						if container, _ := fetchDockerContainer(ctx, containerID, client); container != nil {
+							return fmt.Errorf("expected the container of the job to be removed")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// the removed container of the completed job is kept in the state
+				Config:   config("3", "true", "1m", "v1"),
+				PlanOnly: true,
+			},
+			{
+				Config: config("0", "false", "1m", "v2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "exit_code", "0"),
+					resource.TestCheckResourceAttr(resourceName, "job.0.rerun_on.migration", "v2"),
+				),
+			},
+			{
+				Config:      config("1", "false", "1m", "v3"),
+				ExpectError: regexp.MustCompile(`(?s)failed with exit code 1.*migrating\s+migrated`),
+			},
+			{
+				Config:      strings.Replace(config("0", "true", "2s", "v4"), "exit 0", "sleep 60", 1),
+				ExpectError: regexp.MustCompile(`(?s)the job did not complete within 2s.*migrated`),
+			},
+		},
+	})
+}
+
+func TestAccDockerContainer_desiredState(t *testing.T) {
+	var containerID string
+	resourceName := "docker_container.foo"
+	config := func(desiredState string) string {
+		return fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerDesiredStateConfig"), desiredState)
+	}
+
+	testCheckState := func(expected string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			ctx := context.Background()
+			client, err := testAccProvider.Meta().(*ProviderConfig).MakeClient(ctx, nil)
+			if err != nil {
+				return err
+			}
+			inspected, err := client.ContainerInspect(ctx, containerID)
+			if err != nil {
+				return err
+			}
+			if state := containerStateOf(inspected.State); state != expected {
+				return fmt.Errorf("expected the container to be %s, got %s", expected, state)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("paused"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceID(resourceName, &containerID),
+					testCheckState("paused"),
+					resource.TestCheckResourceAttr(resourceName, "desired_state", "paused"),
+				),
+			},
+			{
+				Config: config("stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckState("stopped"),
+				),
+			},
+			{
+				Config: config("running"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckState("running"),
+				),
+			},
+			{
+				// the paused container drifts from the desired state
+				PreConfig: func() {
+					ctx := context.Background()
+					client, err := testAccProvider.Meta().(*ProviderConfig).MakeClient(ctx, nil)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := client.ContainerPause(ctx, containerID); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             config("running"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config("running"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					testCheckState("running"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccDockerContainer_nameattrnochange(t *testing.T) {
 	var c container.InspectResponse
 	resource.Test(t, resource.TestCase{
@@ -1490,6 +1963,112 @@ func TestAccDockerContainer_cpus(t *testing.T) {
 	})
 }
 
+func TestAccDockerContainer_updateResourcesInPlace(t *testing.T) {
+	var c container.InspectResponse
+	var containerID string
+	resourceName := "docker_container.foo"
+	config := func(cpus, cpuPeriod, cpuQuota, memory, memorySwap, pidsLimit string) string {
+		return fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerUpdateResourcesConfig"), cpus, cpuPeriod, cpuQuota, memory, memorySwap, pidsLimit)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("null", "100000", "50000", "256", "512", "100"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceID(resourceName, &containerID),
+				),
+			},
+			{
+				Config: config("null", "100000", "25000", "384", "768", "200"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					func(*terraform.State) error {
+						if c.HostConfig.CPUQuota != 25000 {
+							return fmt.Errorf("expected the cpu quota to be updated to 25000, got %d", c.HostConfig.CPUQuota)
+						}
+						if c.HostConfig.Memory != 384*1024*1024 {
+							return fmt.Errorf("expected the memory to be updated to 384 MB, got %d", c.HostConfig.Memory)
+						}
+						if c.HostConfig.PidsLimit == nil || *c.HostConfig.PidsLimit != 200 {
+							return fmt.Errorf("expected the pids limit to be updated to 200, got %v", c.HostConfig.PidsLimit)
+						}
+						return nil
+					},
+					resource.TestCheckResourceAttr(resourceName, "pids_limit", "200"),
+				),
+			},
+			{
+				// cpus can not be set on a container with a CPU quota, which replaces the container
+				Config: config(`"1.5"`, "null", "null", "384", "768", "200"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceID(resourceName, &containerID),
+				),
+			},
+			{
+				Config: config(`"0.5"`, "null", "null", "384", "768", "0"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					func(*terraform.State) error {
+						if c.HostConfig.NanoCPUs != 500000000 {
+							return fmt.Errorf("expected cpus to be updated to 0.5, got %d nano CPUs", c.HostConfig.NanoCPUs)
+						}
+						if c.HostConfig.PidsLimit != nil && *c.HostConfig.PidsLimit > 0 {
+							return fmt.Errorf("expected the pids limit to be removed, got %d", *c.HostConfig.PidsLimit)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccDockerContainer_renameInPlace(t *testing.T) {
+	var c container.InspectResponse
+	var containerID string
+	resourceName := "docker_container.foo"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerRenameConfig"), "tf-test-rename-old"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceID(resourceName, &containerID),
+				),
+			},
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_container", "testAccDockerContainerRenameConfig"), "tf-test-rename-new"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccContainerRunning(resourceName, &c),
+					testCheckResourceIDUnchanged(resourceName, &containerID),
+					resource.TestCheckResourceAttr(resourceName, "name", "tf-test-rename-new"),
+					resource.TestCheckResourceAttr(resourceName, "network_data.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "network_data.0.network_name", "tf-test-rename"),
+					func(*terraform.State) error {
+						if c.Name != "/tf-test-rename-new" {
+							return fmt.Errorf("expected the container to be renamed to tf-test-rename-new, got %s", c.Name)
+						}
+						if missing := containerNetworksMissingDNSName(c, "tf-test-rename-new"); len(missing) > 0 {
+							return fmt.Errorf("expected the new name to be a DNS name in networks %v", missing)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 func TestAccDockerContainer_nostart(t *testing.T) {
 	var c container.InspectResponse
 	resource.Test(t, resource.TestCase{
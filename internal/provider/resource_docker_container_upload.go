@@ -0,0 +1,466 @@
+package provider
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/moby/patternmatcher"
+)
+
+// uploadToContainer copies the files of the uploads into the container.
+func uploadToContainer(ctx context.Context, client *client.Client, containerID string, uploads []interface{}) error {
+	for _, upload := range uploads {
+		uploadArchive, err := containerUploadArchive(upload.(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+
+		dstPath := "/"
+		options := container.CopyToContainerOptions{}
+		err = client.CopyToContainer(ctx, containerID, dstPath, uploadArchive, options)
+		uploadArchive.Close() // nolint:errcheck
+		if err != nil {
+			return fmt.Errorf("unable to upload volume content: %w", err)
+		}
+	}
+	return nil
+}
+
+// containerUploadSource returns which one of the mutually exclusive sources of the content
+// of the upload is set.
+func containerUploadSource(upload map[string]interface{}) (string, error) {
+	var setParams []string
+	for _, param := range []string{"content", "content_base64", "source", "source_archive"} {
+		if value, _ := upload[param].(string); value != "" {
+			setParams = append(setParams, param)
+		}
+	}
+
+	if len(setParams) == 0 {
+		return "", fmt.Errorf("error with upload content: one of 'content', 'content_base64', 'source', or 'source_archive' must be set")
+	}
+	if len(setParams) > 1 {
+		return "", fmt.Errorf("error with upload content: only one of 'content', 'content_base64', 'source', or 'source_archive' can be set")
+	}
+	return setParams[0], nil
+}
+
+// containerUploadArchive returns the tar archive with the files of the upload, which are
+// extracted relative to the root of the container. Directories and archives are streamed.
+func containerUploadArchive(upload map[string]interface{}) (io.ReadCloser, error) {
+	uploadSource, err := containerUploadSource(upload)
+	if err != nil {
+		return nil, err
+	}
+
+	file := upload["file"].(string)
+	switch uploadSource {
+	case "source_archive":
+		sourceArchive, err := openSourceArchive(upload["source_archive"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("could not open archive: %w", err)
+		}
+		return streamContainerUploadArchive(func(tw *tar.Writer) error {
+			defer sourceArchive.Close() // nolint:errcheck
+			return relocateTarArchive(tw, tar.NewReader(sourceArchive), file)
+		}), nil
+	case "source":
+		source := upload["source"].(string)
+		info, err := os.Stat(source)
+		if err != nil {
+			return nil, fmt.Errorf("could not read file: %w", err)
+		}
+		if info.IsDir() {
+			entries, err := walkContainerUploadDirectory(source, upload)
+			if err != nil {
+				return nil, fmt.Errorf("could not read directory %s: %w", source, err)
+			}
+			return streamContainerUploadArchive(func(tw *tar.Writer) error {
+				return writeContainerUploadDirectory(tw, entries, file)
+			}), nil
+		}
+	}
+
+	content, err := containerUploadContent(upload, uploadSource)
+	if err != nil {
+		return nil, err
+	}
+
+	executable := upload["executable"].(bool)
+	permission := upload["permissions"].(string)
+
+	var mode int64
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	if permission != "" {
+		mode, err = strconv.ParseInt(permission, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing permission: %w", err)
+		}
+	} else if executable {
+		mode = 0o744
+	} else {
+		mode = 0o644
+	}
+	hdr := &tar.Header{
+		Name:    file,
+		Mode:    mode,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return nil, fmt.Errorf("error creating tar archive: %w", err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return nil, fmt.Errorf("error creating tar archive: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("error creating tar archive: %w", err)
+	}
+
+	return io.NopCloser(buf), nil
+}
+
+// containerUploadContent returns the content of an upload of a single file.
+func containerUploadContent(upload map[string]interface{}, uploadSource string) ([]byte, error) {
+	switch uploadSource {
+	case "content_base64":
+		decoded, _ := base64.StdEncoding.DecodeString(upload["content_base64"].(string))
+		return decoded, nil
+	case "source":
+		sourceContent, err := os.ReadFile(upload["source"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("could not read file: %w", err)
+		}
+		return sourceContent, nil
+	default:
+		return []byte(upload["content"].(string)), nil
+	}
+}
+
+// streamContainerUploadArchive returns the tar archive written by write as a stream.
+func streamContainerUploadArchive(write func(tw *tar.Writer) error) io.ReadCloser {
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pipeWriter)
+		err := write(tw)
+		if err == nil {
+			err = tw.Close()
+		}
+		if err != nil {
+			err = fmt.Errorf("error creating tar archive: %w", err)
+		}
+		pipeWriter.CloseWithError(err) // nolint:errcheck
+	}()
+	return pipeReader
+}
+
+// walkContainerUploadDirectory returns the entries of the source directory of the upload,
+// without the ones matched by `exclude` and, if set, the ones not matched by `include`.
+func walkContainerUploadDirectory(sourceDir string, upload map[string]interface{}) ([]*contextHashEntry, error) {
+	include, _ := upload["include"].([]interface{})
+	exclude, _ := upload["exclude"].([]interface{})
+
+	entries, err := walkBuildContext(sourceDir, stringListToStringSlice(exclude))
+	if err != nil {
+		return nil, err
+	}
+	if len(include) == 0 {
+		return entries, nil
+	}
+
+	pm, err := patternmatcher.New(stringListToStringSlice(include))
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(entries, func(entry *contextHashEntry) bool {
+		included, err := pm.MatchesOrParentMatches(entry.relPath)
+		return err != nil || !included
+	}), nil
+}
+
+// writeContainerUploadDirectory writes the entries of a directory into the tar archive below
+// the target directory, with the modes of the entries. The files are owned by root, like
+// the single files which are uploaded.
+func writeContainerUploadDirectory(tw *tar.Writer, entries []*contextHashEntry, targetDir string) error {
+	for _, entry := range entries {
+		var link string
+		if entry.info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(entry.absPath)
+			if err != nil {
+				return err
+			}
+			link = target
+		}
+
+		hdr, err := tar.FileInfoHeader(entry.info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(targetDir, filepath.ToSlash(entry.relPath))
+		if entry.info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !entry.info.Mode().IsRegular() {
+			continue
+		}
+
+		f, err := os.Open(entry.absPath)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		f.Close() // nolint:errcheck
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relocateTarArchive copies the entries of the archive into the tar archive below the target
+// directory.
+func relocateTarArchive(tw *tar.Writer, tr *tar.Reader, targetDir string) error {
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name, err := relocateTarEntryName(targetDir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			name += "/"
+		}
+		hdr.Name = name
+		if hdr.Typeflag == tar.TypeLink {
+			if hdr.Linkname, err = relocateTarEntryName(targetDir, hdr.Linkname); err != nil {
+				return err
+			}
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// relocateTarEntryName returns the path of an entry of an archive in targetDir, and fails if it
+// is outside of it.
+func relocateTarEntryName(targetDir, name string) (string, error) {
+	relocated := path.Join(targetDir, name)
+	if !strings.HasPrefix(relocated+"/", strings.TrimSuffix(path.Clean(targetDir), "/")+"/") {
+		return "", fmt.Errorf("entry %s of the archive is outside of %s", name, targetDir)
+	}
+	return relocated, nil
+}
+
+// containerUploadHash returns the sha256 hash of the content of the upload. For directories it
+// is a hash over the paths, modes and contents of the files which are uploaded.
+func containerUploadHash(ctx context.Context, upload map[string]interface{}) (string, error) {
+	uploadSource, err := containerUploadSource(upload)
+	if err != nil {
+		return "", err
+	}
+
+	switch uploadSource {
+	case "source_archive":
+		return calculateSourceArchiveChecksum(ctx, upload["source_archive"].(string))
+	case "source":
+		source := upload["source"].(string)
+		info, err := os.Stat(source)
+		if err != nil {
+			return "", err
+		}
+		if !info.IsDir() {
+			return digestFileCached(source, info)
+		}
+
+		entries, err := walkContainerUploadDirectory(source, upload)
+		if err != nil {
+			return "", err
+		}
+		if err := digestBuildContextEntries(ctx, entries); err != nil {
+			return "", err
+		}
+		return sumContextHashEntries(entries), nil
+	}
+
+	content, err := containerUploadContent(upload, uploadSource)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// containerUploadHashes returns the hashes of the contents of the uploads by their file.
+func containerUploadHashes(ctx context.Context, uploads []interface{}) (map[string]interface{}, error) {
+	hashes := make(map[string]interface{}, len(uploads))
+	for _, upload := range uploads {
+		upload := upload.(map[string]interface{})
+		hash, err := containerUploadHash(ctx, upload)
+		if err != nil {
+			return nil, fmt.Errorf("unable to hash the upload of %s: %w", upload["file"], err)
+		}
+		hashes[upload["file"].(string)] = hash
+	}
+	return hashes, nil
+}
+
+// updateContainerUploads uploads the changed files into the container, and restarts or
+// signals the running container afterwards if requested by the changed uploads. The files of
+// removed uploads are left in the container. A restart runs the pre_stop_exec and
+// post_start_exec hooks.
+func updateContainerUploads(ctx context.Context, client *client.Client, d *schema.ResourceData) diag.Diagnostics {
+	oldUploads, newUploads := d.GetChange("upload")
+	oldHashes, _ := d.GetChange("upload_sha256")
+	newHashes, err := containerUploadHashes(ctx, newUploads.(*schema.Set).List())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	changedUploads := changedContainerUploads(oldUploads.(*schema.Set).List(), newUploads.(*schema.Set).List(), oldHashes.(map[string]interface{}), newHashes)
+	if err := d.Set("upload_sha256", newHashes); err != nil {
+		return diag.FromErr(err)
+	}
+	if len(changedUploads) == 0 {
+		return nil
+	}
+
+	log.Printf("[INFO] Uploading %d changed files into container %s", len(changedUploads), d.Id())
+	if err := uploadToContainer(ctx, client, d.Id(), changedUploads); err != nil {
+		return diag.FromErr(err)
+	}
+
+	restart := false
+	var signals []string
+	for _, upload := range changedUploads {
+		upload := upload.(map[string]interface{})
+		if upload["restart_on_change"].(bool) {
+			restart = true
+		}
+		if signal := upload["signal_on_change"].(string); signal != "" && !slices.Contains(signals, signal) {
+			signals = append(signals, signal)
+		}
+	}
+	if !restart && len(signals) == 0 {
+		return nil
+	}
+
+	inspected, err := client.ContainerInspect(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("unable to inspect container %s: %w", d.Id(), err))
+	}
+	if inspected.State == nil || !inspected.State.Running || inspected.State.Paused {
+		log.Printf("[DEBUG] Container %s is not running, the changed uploads are applied on its next start", d.Id())
+		return nil
+	}
+
+	if restart {
+		diags := runContainerExecHooks(ctx, client, d, containerPreStopExec)
+		if diags.HasError() {
+			return diags
+		}
+		log.Printf("[INFO] Restarting container %s for the changed uploads", d.Id())
+		if err := client.ContainerRestart(ctx, d.Id(), container.StopOptions{}); err != nil {
+			return append(diags, diag.FromErr(fmt.Errorf("unable to restart container %s: %w", d.Id(), err))...)
+		}
+		return append(diags, runContainerExecHooks(ctx, client, d, containerPostStartExec)...)
+	}
+	for _, signal := range signals {
+		log.Printf("[INFO] Sending %s to container %s for the changed uploads", signal, d.Id())
+		if err := client.ContainerKill(ctx, d.Id(), signal); err != nil {
+			return diag.FromErr(fmt.Errorf("unable to send %s to container %s: %w", signal, d.Id(), err))
+		}
+	}
+	return nil
+}
+
+// containerUploadContentAttributes are the attributes of an upload which change the uploaded
+// file, unlike the attributes which only control what happens on a change.
+var containerUploadContentAttributes = []string{
+	"file", "content", "content_base64", "source", "source_archive", "source_hash", "include", "exclude", "executable", "permissions",
+}
+
+// changedContainerUploads returns the new uploads whose file is not uploaded the same way by
+// one of the old uploads, or whose content hash changed. Containers created before the hashes
+// were recorded only upload changed attributes.
+func changedContainerUploads(oldUploads, newUploads []interface{}, oldHashes, newHashes map[string]interface{}) []interface{} {
+	uploadKey := func(upload interface{}) string {
+		values := make([]string, 0, len(containerUploadContentAttributes))
+		for _, attribute := range containerUploadContentAttributes {
+			values = append(values, fmt.Sprintf("%v", upload.(map[string]interface{})[attribute]))
+		}
+		return strings.Join(values, "\x00")
+	}
+
+	oldKeys := make(map[string]bool, len(oldUploads))
+	for _, upload := range oldUploads {
+		oldKeys[uploadKey(upload)] = true
+	}
+
+	var changed []interface{}
+	for _, upload := range newUploads {
+		file := upload.(map[string]interface{})["file"].(string)
+		oldHash, hashed := oldHashes[file]
+		if !oldKeys[uploadKey(upload)] || (hashed && oldHash != newHashes[file]) {
+			changed = append(changed, upload)
+		}
+	}
+	return changed
+}
+
+// diffContainerUploadHashes plans the upload of the files whose content changed, which is not
+// visible in the configuration for `source` files, directories and archives.
+func diffContainerUploadHashes(ctx context.Context, d *schema.ResourceDiff) error {
+	if rawUpload := d.GetRawConfig().GetAttr("upload"); !rawUpload.IsNull() && !rawUpload.IsWhollyKnown() {
+		return d.SetNewComputed("upload_sha256")
+	}
+
+	hashes, err := containerUploadHashes(ctx, d.Get("upload").(*schema.Set).List())
+	if err != nil {
+		// the sources might be created during the apply, e.g. by another resource
+		log.Printf("[WARN] %s, the uploads of container %s are hashed during the apply", err, d.Id())
+		return d.SetNewComputed("upload_sha256")
+	}
+
+	oldHashes, _ := d.GetChange("upload_sha256")
+	if reflect.DeepEqual(oldHashes.(map[string]interface{}), hashes) {
+		return nil
+	}
+	return d.SetNew("upload_sha256", hashes)
+}
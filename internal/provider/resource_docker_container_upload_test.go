@@ -0,0 +1,357 @@
+package provider
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestChangedContainerUploads(t *testing.T) {
+	upload := func(file, content string, signal string) map[string]interface{} {
+		return map[string]interface{}{
+			"file":              file,
+			"content":           content,
+			"content_base64":    "",
+			"source":            "",
+			"source_hash":       "",
+			"executable":        false,
+			"permissions":       "",
+			"restart_on_change": false,
+			"signal_on_change":  signal,
+		}
+	}
+
+	oldUploads := []interface{}{
+		upload("/etc/nginx/nginx.conf", "v1", ""),
+		upload("/etc/nginx/mime.types", "types", ""),
+	}
+	newUploads := []interface{}{
+		upload("/etc/nginx/nginx.conf", "v2", "SIGHUP"),
+		upload("/etc/nginx/mime.types", "types", "SIGHUP"),
+		upload("/etc/nginx/conf.d/app.conf", "app", ""),
+	}
+
+	changed := changedContainerUploads(oldUploads, newUploads, nil, nil)
+	expected := []interface{}{newUploads[0], newUploads[2]}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("expected %v, got %v", expected, changed)
+	}
+
+	// a changed content also changes the recorded hash, the upload is still only returned once
+	oldHashes := map[string]interface{}{
+		"/etc/nginx/nginx.conf": "hash-v1",
+		"/etc/nginx/mime.types": "hash-types",
+	}
+	newHashes := map[string]interface{}{
+		"/etc/nginx/nginx.conf":      "hash-v2",
+		"/etc/nginx/mime.types":      "hash-types",
+		"/etc/nginx/conf.d/app.conf": "hash-app",
+	}
+	changed = changedContainerUploads(oldUploads, newUploads, oldHashes, newHashes)
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("expected %v with hashes, got %v", expected, changed)
+	}
+
+	// the content of a source can change without a change of the configuration
+	newHashes["/etc/nginx/mime.types"] = "hash-types-v2"
+	changed = changedContainerUploads(oldUploads, newUploads, oldHashes, newHashes)
+	expected = []interface{}{newUploads[0], newUploads[1], newUploads[2]}
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("expected %v with a changed hash, got %v", expected, changed)
+	}
+}
+
+func TestContainerUploadArchive(t *testing.T) {
+	archive, err := containerUploadArchive(map[string]interface{}{
+		"file":           "/terraform/test.sh",
+		"content":        "",
+		"content_base64": "ZWNobyBoZWxsbw==",
+		"source":         "",
+		"executable":     false,
+		"permissions":    "0750",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tr := tar.NewReader(archive)
+	header, err := tr.Next()
+	if err != nil {
+		t.Fatalf("unable to read the archive: %s", err)
+	}
+	content, _ := io.ReadAll(tr)
+	if header.Name != "/terraform/test.sh" || header.Mode != 0o750 || string(content) != "echo hello" {
+		t.Fatalf("unexpected file %s with mode %o and content %q", header.Name, header.Mode, content)
+	}
+
+	if _, err := containerUploadArchive(map[string]interface{}{
+		"file":           "/terraform/test.sh",
+		"content":        "echo hello",
+		"content_base64": "ZWNobyBoZWxsbw==",
+		"source":         "",
+	}); err == nil {
+		t.Fatalf("expected an error for both content and content_base64")
+	}
+}
+
+func TestContainerUploadArchive_Directory(t *testing.T) {
+	sourceDir := t.TempDir()
+	for name, content := range map[string]string{
+		"nginx.conf":         "events {}",
+		"conf.d/app.conf":    "server {}",
+		"conf.d/app.conf~":   "backup",
+		"html/index.html":    "<h1>hello</h1>",
+		"scripts/reload.sh":  "nginx -s reload",
+		"scripts/.gitignore": "*",
+	} {
+		writeTestUploadFile(t, filepath.Join(sourceDir, name), content)
+	}
+	if err := os.Chmod(filepath.Join(sourceDir, "scripts/reload.sh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := containerUploadArchive(map[string]interface{}{
+		"file":           "/etc/nginx",
+		"content":        "",
+		"content_base64": "",
+		"source":         sourceDir,
+		"source_archive": "",
+		"include":        []interface{}{"*.conf", "conf.d", "scripts"},
+		"exclude":        []interface{}{"**/*~", "**/.gitignore"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer archive.Close() // nolint:errcheck
+
+	files := readTestArchive(t, archive)
+	expected := map[string]string{
+		"/etc/nginx/conf.d/":           "",
+		"/etc/nginx/conf.d/app.conf":   "server {}",
+		"/etc/nginx/nginx.conf":        "events {}",
+		"/etc/nginx/scripts/":          "",
+		"/etc/nginx/scripts/reload.sh": "nginx -s reload",
+	}
+	contents := make(map[string]string, len(files))
+	for name, file := range files {
+		contents[name] = file.content
+	}
+	if !reflect.DeepEqual(contents, expected) {
+		t.Fatalf("expected %v, got %v", expected, contents)
+	}
+	if mode := files["/etc/nginx/scripts/reload.sh"].header.Mode; mode != 0o755 {
+		t.Fatalf("expected the mode of reload.sh to be preserved, got %o", mode)
+	}
+	if uid := files["/etc/nginx/nginx.conf"].header.Uid; uid != 0 {
+		t.Fatalf("expected the files to be owned by root, got uid %d", uid)
+	}
+}
+
+func TestContainerUploadArchive_SourceArchive(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "bin/app", Typeflag: tar.TypeReg, Mode: 0o755, Size: 3},
+		{Name: "bin/app-link", Typeflag: tar.TypeLink, Linkname: "bin/app"},
+	} {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Size > 0 {
+			if _, err := tw.Write([]byte("app")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "app.tar")
+	writeTestUploadFile(t, archivePath, buf.String())
+
+	upload := map[string]interface{}{
+		"file":           "/opt/app",
+		"content":        "",
+		"content_base64": "",
+		"source":         "",
+		"source_archive": archivePath,
+	}
+	archive, err := containerUploadArchive(upload)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer archive.Close() // nolint:errcheck
+
+	files := readTestArchive(t, archive)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	if expected := []string{"/opt/app/bin/", "/opt/app/bin/app", "/opt/app/bin/app-link"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	if linkname := files["/opt/app/bin/app-link"].header.Linkname; linkname != "/opt/app/bin/app" {
+		t.Fatalf("expected the hardlink to be relocated, got %s", linkname)
+	}
+}
+
+func TestRelocateTarArchive_OutsideOfTarget(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "../../etc/passwd", Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := relocateTarArchive(tar.NewWriter(io.Discard), tar.NewReader(&buf), "/opt/app"); err == nil {
+		t.Fatalf("expected an error for an entry outside of the target directory")
+	}
+}
+
+func TestRelocateTarArchive_HardlinkOutsideOfTarget(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "passwd", Linkname: "../../etc/passwd", Typeflag: tar.TypeLink}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := relocateTarArchive(tar.NewWriter(io.Discard), tar.NewReader(&buf), "/opt/app"); err == nil {
+		t.Fatalf("expected an error for a hardlink to a file outside of the target directory")
+	}
+}
+
+func TestRelocateTarArchive_Root(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "etc/app.conf", Typeflag: tar.TypeReg, Mode: 0644, Size: 2},
+		{Name: "etc/app.link", Linkname: "etc/app.conf", Typeflag: tar.TypeLink},
+	} {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Size > 0 {
+			if _, err := tw.Write([]byte("v1")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var relocated bytes.Buffer
+	relocatedWriter := tar.NewWriter(&relocated)
+	if err := relocateTarArchive(relocatedWriter, tar.NewReader(&buf), "/"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := relocatedWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readTestArchive(t, &relocated)
+	if _, ok := files["/etc/"]; !ok {
+		t.Errorf("expected /etc/ in the archive, got %v", files)
+	}
+	if files["/etc/app.conf"].content != "v1" {
+		t.Errorf("expected /etc/app.conf with content v1, got %v", files)
+	}
+	if link, ok := files["/etc/app.link"]; !ok || link.header.Linkname != "/etc/app.conf" {
+		t.Errorf("expected /etc/app.link to link to /etc/app.conf, got %v", files)
+	}
+}
+
+func TestContainerUploadHash(t *testing.T) {
+	ctx := context.Background()
+	sourceDir := t.TempDir()
+	writeTestUploadFile(t, filepath.Join(sourceDir, "app.conf"), "v1")
+	writeTestUploadFile(t, filepath.Join(sourceDir, "app.conf~"), "backup")
+
+	upload := map[string]interface{}{
+		"file":           "/etc/app",
+		"content":        "",
+		"content_base64": "",
+		"source":         sourceDir,
+		"source_archive": "",
+		"include":        []interface{}{},
+		"exclude":        []interface{}{"*~"},
+	}
+	hash, err := containerUploadHash(ctx, upload)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	writeTestUploadFile(t, filepath.Join(sourceDir, "app.conf~"), "another backup")
+	if excludedHash, _ := containerUploadHash(ctx, upload); excludedHash != hash {
+		t.Fatalf("expected the hash not to change for an excluded file")
+	}
+
+	writeTestUploadFile(t, filepath.Join(sourceDir, "app.conf"), "v2")
+	if changedHash, _ := containerUploadHash(ctx, upload); changedHash == hash {
+		t.Fatalf("expected the hash to change for a changed file")
+	}
+
+	contentHash, err := containerUploadHash(ctx, map[string]interface{}{
+		"content":        "hello",
+		"content_base64": "",
+		"source":         "",
+		"source_archive": "",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"; contentHash != expected {
+		t.Fatalf("expected %s, got %s", expected, contentHash)
+	}
+}
+
+type testArchiveFile struct {
+	header  *tar.Header
+	content string
+}
+
+func readTestArchive(t *testing.T, archive io.Reader) map[string]testArchiveFile {
+	t.Helper()
+
+	files := map[string]testArchiveFile{}
+	tr := tar.NewReader(archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatalf("unable to read the archive: %s", err)
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("unable to read %s from the archive: %s", hdr.Name, err)
+		}
+		files[hdr.Name] = testArchiveFile{header: hdr, content: string(content)}
+	}
+}
+
+func writeTestUploadFile(t *testing.T, filePath, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -10,6 +10,9 @@ const (
 	dockerImageCreateDefaultTimeout = 20 * time.Minute
 	dockerImageUpdateDefaultTimeout = 20 * time.Minute
 	dockerImageDeleteDefaultTimeout = 20 * time.Minute
+
+	imageRemoveRefreshMinTimeout = 2 * time.Second
+	imageRemoveConflictTimeout   = 30 * time.Second
 )
 
 func resourceDockerImage() *schema.Resource {
@@ -20,6 +23,7 @@ func resourceDockerImage() *schema.Resource {
 		ReadContext:   resourceDockerImageRead,
 		UpdateContext: resourceDockerImageUpdate,
 		DeleteContext: resourceDockerImageDelete,
+		CustomizeDiff: resourceDockerImageCustomizeDiff,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(dockerImageCreateDefaultTimeout),
@@ -52,6 +56,47 @@ func resourceDockerImage() *schema.Resource {
 				Computed:    true,
 			},
 
+			"build_metadata": {
+				Type:        schema.TypeList,
+				Description: "Metadata of the last build of the image. Only populated when the image was built with a buildx builder.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"image_digest": {
+							Type:        schema.TypeString,
+							Description: "The digest of the built image (`containerimage.digest`). Can be used to pin the exact image which was built.",
+							Computed:    true,
+						},
+						"config_digest": {
+							Type:        schema.TypeString,
+							Description: "The digest of the image config (`containerimage.config.digest`).",
+							Computed:    true,
+						},
+						"build_ref": {
+							Type:        schema.TypeString,
+							Description: "The reference of the build in the form of `<builder>/<node>/<ref>` (`buildx.build.ref`). Can be used to look up the build with `docker buildx history`.",
+							Computed:    true,
+						},
+						"warnings": {
+							Type:        schema.TypeList,
+							Description: "The warnings which were emitted during the build, e.g. Dockerfile lint warnings.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"builder_name": {
+							Type:        schema.TypeString,
+							Description: "The name of the buildx builder which was used for the build.",
+							Computed:    true,
+						},
+						"builder_source": {
+							Type:        schema.TypeString,
+							Description: "How the builder was resolved. One of `explicit`, `env:BUILDX_BUILDER`, `buildx-store` or `docker-context`.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+
 			"keep_locally": {
 				Type:        schema.TypeBool,
 				Description: "If true, then the Docker image won't be deleted on destroy operation. If this is false, it will delete the image from the docker local storage on destroy operation.",
@@ -69,17 +114,50 @@ func resourceDockerImage() *schema.Resource {
 
 			"force_remove": {
 				Type:        schema.TypeBool,
-				Description: "If true, then the image is removed forcibly when the resource is destroyed.",
+				Description: "If true, then the image is removed forcibly when the resource is destroyed. Otherwise only the tag given by `name` is removed as long as other tags reference the image, and the image is left in place with a warning as long as containers use it.",
 				Optional:    true,
 			},
 
+			"source_archive": {
+				Type:          schema.TypeList,
+				Description:   "Loads the image from a tar archive, as created by `docker image save`, or from an OCI layout directory instead of pulling it, similar to `docker image load`. If the archive does not contain an image tagged as `name`, it must contain a single image which is then tagged as `name`. A change of the checksum of the archive reloads the image.",
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"build", "pull_triggers"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:        schema.TypeString,
+							Description: "The path to the tar archive, which may be compressed with gzip, bzip2, xz or zstd, or the OCI layout directory.",
+							Required:    true,
+						},
+						"platform": {
+							Type:        schema.TypeString,
+							Description: "The platform to load from a multi-platform archive, e.g. `linux/amd64`.",
+							Optional:    true,
+						},
+						"digest": {
+							Type:             schema.TypeString,
+							Description:      "The expected digest of the image in the form of `sha256:<hash>`. Loading fails if the loaded image has another ID or manifest digest, and the image is reloaded if `name` refers to another image later on.",
+							Optional:         true,
+							ValidateDiagFunc: validateStringMatchesPattern(`^sha256:[a-f0-9]{64}$`),
+						},
+						"checksum": {
+							Type:        schema.TypeString,
+							Description: "The sha256 checksum of the archive the image was loaded from. For a directory, the hash over all its files.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+
 			"build": {
 				Type:          schema.TypeSet,
 				Description:   "Configuration to build an image. Requires the `Use containerd for pulling and storing images` option to be disabled in the Docker Host(https://github.com/kreuzwerker/terraform-provider-docker/issues/534). Please see [docker build command reference](https://docs.docker.com/engine/reference/commandline/build/#options) too.",
 				Optional:      true,
 				MaxItems:      1,
 				ConflictsWith: []string{"pull_triggers"},
-				Elem:          buildSchema,
+				Elem:          imageBuildSchema(),
 			},
 			"triggers": {
 				Description: "A map of arbitrary strings that, when changed, will force the `docker_image` resource to be replaced. This can be used to rebuild an image when contents of source code folders change",
@@ -151,6 +229,32 @@ var buildSchema = &schema.Resource{
 				},
 			},
 		},
+		"ssh": {
+			Type:        schema.TypeList,
+			Description: "SSH agent sockets or keys to expose to the build, e.g. for `RUN --mount=type=ssh`. The entries are validated at plan time.",
+			Optional:    true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:             schema.TypeString,
+						Description:      "ID of the SSH agent, which is referenced with `RUN --mount=type=ssh,id=<id>`. Defaults to `default`.",
+						Optional:         true,
+						Default:          "default",
+						ForceNew:         true,
+						ValidateDiagFunc: validateStringMatchesPattern(`^[a-zA-Z0-9_.-]+$`),
+					},
+					"paths": {
+						Type:        schema.TypeList,
+						Description: "Paths to an SSH agent socket or to private keys. Passphrase protected keys are not supported. If empty, the socket in the `SSH_AUTH_SOCK` environment variable is used.",
+						Optional:    true,
+						ForceNew:    true,
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+						},
+					},
+				},
+			},
+		},
 		"label": {
 			Type:        schema.TypeMap,
 			Description: "Set metadata for an image",
@@ -477,3 +581,18 @@ var buildSchema = &schema.Resource{
 		},
 	},
 }
+
+// imageBuildSchema is the build schema of docker_image, which records the hash of the build
+// context to rebuild the image when it changes.
+func imageBuildSchema() *schema.Resource {
+	imageSchema := make(map[string]*schema.Schema, len(buildSchema.Schema)+1)
+	for key, value := range buildSchema.Schema {
+		imageSchema[key] = value
+	}
+	imageSchema["context_hash"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The sha256 hash of the build context, the Dockerfile and the build args the image was built from. Files excluded by the `.dockerignore` file are not taken into account. A change of the hash forces a rebuild of the image.",
+		Computed:    true,
+	}
+	return &schema.Resource{Schema: imageSchema}
+}
@@ -15,18 +15,22 @@ import (
 	"strings"
 
 	"github.com/containerd/errdefs"
+	"github.com/distribution/reference"
 	"github.com/docker/cli/cli/command/image/build"
 	dockerBuildTypes "github.com/docker/docker/api/types/build"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/mitchellh/go-homedir"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/secrets/secretsprovider"
+	"github.com/moby/buildkit/session/sshforward/sshprovider"
 	"github.com/moby/go-archive"
 	"github.com/pkg/errors"
 )
@@ -39,13 +43,22 @@ func resourceDockerImageCreate(ctx context.Context, d *schema.ResourceData, meta
 	imageName := d.Get("name").(string)
 
 	if value, ok := d.GetOk("build"); ok {
+		if err := setBuildContextHash(ctx, d); err != nil {
+			return diag.Errorf("Unable to calculate build context hash: %s", err)
+		}
 		for _, rawBuild := range value.(*schema.Set).List() {
-			shouldReturn, d1 := buildImage(ctx, rawBuild, client, imageName)
+			result, shouldReturn, d1 := buildImage(ctx, rawBuild, client, imageName)
 			if shouldReturn {
 				return d1
 			}
+			if err := d.Set("build_metadata", flattenBuildResult(result)); err != nil {
+				log.Printf("[WARN] failed to set build metadata from build result: %s", err)
+			}
 		}
 	}
+	if err := loadImageSourceArchive(ctx, d, client, imageName); err != nil {
+		return diag.Errorf("Unable to load Docker image from source archive: %s", err)
+	}
 	apiImage, err := findImage(ctx, imageName, client, meta.(*ProviderConfig).AuthConfigs, d.Get("platform").(string))
 	if err != nil {
 		return diag.Errorf("Unable to read Docker image into resource: %s", err)
@@ -55,13 +68,13 @@ func resourceDockerImageCreate(ctx context.Context, d *schema.ResourceData, meta
 	return resourceDockerImageRead(ctx, d, meta)
 }
 
-func buildImage(ctx context.Context, rawBuild interface{}, client *client.Client, imageName string) (bool, diag.Diagnostics) {
+func buildImage(ctx context.Context, rawBuild interface{}, client *client.Client, imageName string) (*buildResult, bool, diag.Diagnostics) {
 	rawBuildValue := rawBuild.(map[string]interface{})
 	useLegacyBuilder, _ := rawBuildValue["use_legacy_builder"].(bool)
 	// now we need to determine whether we can use buildx or need to use the legacy builder
 	canUseBuildx, err := canUseBuildx(ctx, client)
 	if err != nil {
-		return true, diag.FromErr(err)
+		return nil, true, diag.FromErr(err)
 	}
 	if useLegacyBuilder {
 		log.Printf("[DEBUG] use_legacy_builder=true, forcing legacy builder")
@@ -74,30 +87,49 @@ func buildImage(ctx context.Context, rawBuild interface{}, client *client.Client
 		log.Printf("[DEBUG] Using buildx")
 		dockerCli, err := createAndInitDockerCli(client)
 		if err != nil {
-			return true, diag.FromErr(fmt.Errorf("failed to create and init Docker CLI: %w", err))
+			return nil, true, diag.FromErr(fmt.Errorf("failed to create and init Docker CLI: %w", err))
 		}
 
 		options, err := mapBuildAttributesToBuildOptions(rawBuildValue, imageName, dockerCli)
 
 		if err != nil {
-			return true, diag.FromErr(fmt.Errorf("Error mapping build attributes: %v", err))
+			return nil, true, diag.FromErr(fmt.Errorf("Error mapping build attributes: %v", err))
 		}
 		buildLogFile := rawBuildValue["build_log_file"].(string)
 
 		log.Printf("[DEBUG] build options %#v", options)
 
-		err = runBuild(ctx, dockerCli, options, buildLogFile)
+		result, err := runBuild(ctx, dockerCli, options, buildLogFile)
 		if err != nil {
-			return true, diag.Errorf("Error running buildx build: %v", err)
+			return nil, true, diag.Errorf("Error running buildx build: %v", err)
 		}
-	} else {
+		return result, false, nil
+	}
 
-		err := legacyBuildDockerImage(ctx, rawBuildValue, imageName, client)
-		if err != nil {
-			return true, diag.Errorf("Error running legacy build: %v", err)
-		}
+	err = legacyBuildDockerImage(ctx, rawBuildValue, imageName, client)
+	if err != nil {
+		return nil, true, diag.Errorf("Error running legacy build: %v", err)
+	}
+	return nil, false, nil
+}
+
+// flattenBuildResult maps the metadata of a buildx build to the build_metadata attribute.
+// Builds with the legacy builder do not return any metadata.
+func flattenBuildResult(result *buildResult) []interface{} {
+	if result == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"image_digest":   result.imageDigest,
+			"config_digest":  result.configDigest,
+			"build_ref":      result.buildRef,
+			"warnings":       result.warnings,
+			"builder_name":   result.builderName,
+			"builder_source": string(result.builderSource),
+		},
 	}
-	return false, nil
 }
 
 func resourceDockerImageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -122,6 +154,12 @@ func resourceDockerImageRead(ctx context.Context, d *schema.ResourceData, meta i
 		return nil
 	}
 
+	if sourceArchives := d.Get("source_archive").([]interface{}); len(sourceArchives) > 0 && sourceArchives[0] != nil {
+		if err := checkSourceArchiveDigest(ctx, d, client, imageName); err != nil {
+			return diag.Errorf("resourceDockerImageRead: error inspecting image %q: %s", imageName, err)
+		}
+	}
+
 	repoDigest := determineRepoDigest(imageName, foundImage)
 
 	// TODO mavogel: remove the appended name from the ID
@@ -139,6 +177,11 @@ func resourceDockerImageUpdate(ctx context.Context, d *schema.ResourceData, meta
 		return diag.FromErr(fmt.Errorf("failed to create Docker client: %w", err))
 	}
 	imageName := d.Get("name").(string)
+	if d.HasChanges("source_archive", "image_id") {
+		if err := loadImageSourceArchive(ctx, d, client, imageName); err != nil {
+			return diag.Errorf("Unable to load Docker image from source archive: %s", err)
+		}
+	}
 	_, err = findImage(ctx, imageName, client, meta.(*ProviderConfig).AuthConfigs, d.Get("platform").(string))
 	if err != nil {
 		return diag.Errorf("Unable to read Docker image into resource: %s", err)
@@ -152,8 +195,16 @@ func resourceDockerImageDelete(ctx context.Context, d *schema.ResourceData, meta
 	if err != nil {
 		return diag.FromErr(fmt.Errorf("failed to create Docker client: %w", err))
 	}
-	// TODO mavogel: add retries. see e.g. service updateFailsAndRollbackConvergeConfig test
 	err = removeImage(ctx, d, client)
+	var inUseErr *imageInUseError
+	if errors.As(err, &inUseErr) {
+		d.SetId("")
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Image is still used by containers",
+			Detail:   fmt.Sprintf("The image %s was not removed as it is still used by the containers %s. Remove it manually once the containers are removed.", inUseErr.imageName, strings.Join(inUseErr.containerIDs, ", ")),
+		}}
+	}
 	if err != nil {
 		return diag.Errorf("Unable to remove Docker image: %s", err)
 	}
@@ -161,6 +212,17 @@ func resourceDockerImageDelete(ctx context.Context, d *schema.ResourceData, meta
 	return nil
 }
 
+// imageInUseError is returned by removeImage if the image is not removed because
+// containers still use it.
+type imageInUseError struct {
+	imageName    string
+	containerIDs []string
+}
+
+func (e *imageInUseError) Error() string {
+	return fmt.Sprintf("image %s is still used by the containers %s", e.imageName, strings.Join(e.containerIDs, ", "))
+}
+
 // Helpers
 func searchLocalImages(ctx context.Context, client *client.Client, data Data, imageName string) (*image.Summary, error) {
 	imageInspect, err := client.ImageInspect(ctx, imageName)
@@ -184,6 +246,10 @@ func searchLocalImages(ctx context.Context, client *client.Client, data Data, im
 	return nil, nil
 }
 
+// removeImage removes the reference of the image owned by the resource. The image itself is
+// only removed if neither other tags nor containers reference it, unless force_remove is set.
+// Images which are still used by containers are left in place, and conflicts reported by the
+// daemon are only retried for a short time, as they are usually transient.
 func removeImage(ctx context.Context, d *schema.ResourceData, client *client.Client) error {
 	var data Data
 
@@ -204,21 +270,110 @@ func removeImage(ctx context.Context, d *schema.ResourceData, client *client.Cli
 	if err != nil {
 		return fmt.Errorf("removeImage: error looking up local image %q: %w", imageName, err)
 	}
+	if foundImage == nil {
+		return nil
+	}
 
-	if foundImage != nil {
-		imageDeleteResponseItems, err := client.ImageRemove(ctx, imageName, image.RemoveOptions{
-			Force: d.Get("force_remove").(bool),
-		})
-		if err != nil {
-			return err
+	if d.Get("force_remove").(bool) {
+		return removeImageReference(ctx, client, imageName, true)
+	}
+
+	if otherTags := otherImageTags(imageName, foundImage.RepoTags); len(otherTags) > 0 {
+		// the daemon only untags the image as long as other tags reference it
+		log.Printf("[DEBUG] Image %s is also tagged as %s, only removing tag %s", foundImage.ID, strings.Join(otherTags, ", "), imageName)
+		return removeImageReference(ctx, client, imageName, false)
+	}
+
+	containerIDs, err := containersUsingImage(ctx, client, foundImage.ID)
+	if err != nil {
+		return err
+	}
+	if len(containerIDs) > 0 {
+		return &imageInUseError{imageName: imageName, containerIDs: containerIDs}
+	}
+
+	stateConf := &retry.StateChangeConf{
+		Pending:    []string{"conflict"},
+		Target:     []string{"removed"},
+		Refresh:    resourceDockerImageRemoveRefreshFunc(ctx, client, imageName, foundImage.ID),
+		Timeout:    min(imageRemoveConflictTimeout, d.Timeout(schema.TimeoutDelete)),
+		MinTimeout: imageRemoveRefreshMinTimeout,
+	}
+	_, err = stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+func resourceDockerImageRemoveRefreshFunc(ctx context.Context, client *client.Client, imageName string, imageID string) retry.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		if err := removeImageReference(ctx, client, imageName, false); err != nil {
+			if errdefs.IsConflict(err) {
+				log.Printf("[INFO] Image %s is still in use: %v", imageName, err)
+				return imageID, "conflict", nil
+			}
+			return nil, "", err
 		}
-		indentedImageDeleteResponseItems, _ := json.MarshalIndent(imageDeleteResponseItems, "", "\t")
-		log.Printf("[DEBUG] Deleted image items: \n%s", indentedImageDeleteResponseItems)
+		return imageID, "removed", nil
 	}
+}
 
+func removeImageReference(ctx context.Context, client *client.Client, imageName string, force bool) error {
+	imageDeleteResponseItems, err := client.ImageRemove(ctx, imageName, image.RemoveOptions{
+		Force: force,
+	})
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	indentedImageDeleteResponseItems, _ := json.MarshalIndent(imageDeleteResponseItems, "", "\t")
+	log.Printf("[DEBUG] Deleted image items: \n%s", indentedImageDeleteResponseItems)
 	return nil
 }
 
+// otherImageTags returns the tags of an image besides the one given by imageName. A name
+// without tag and digest refers to the latest tag, a name with only a digest to no tag.
+func otherImageTags(imageName string, repoTags []string) []string {
+	ownTag := ""
+	if named, err := reference.ParseNormalizedNamed(imageName); err == nil {
+		if tagged, ok := named.(reference.Tagged); ok {
+			ownTag = reference.FamiliarName(named) + ":" + tagged.Tag()
+		} else if _, ok := named.(reference.Digested); !ok {
+			ownTag = reference.FamiliarString(reference.TagNameOnly(named))
+		}
+	}
+
+	otherTags := make([]string, 0)
+	for _, repoTag := range repoTags {
+		if repoTag == "<none>:<none>" {
+			continue
+		}
+		if named, err := reference.ParseNormalizedNamed(repoTag); err == nil {
+			repoTag = reference.FamiliarString(reference.TagNameOnly(named))
+		}
+		if repoTag != ownTag {
+			otherTags = append(otherTags, repoTag)
+		}
+	}
+	return otherTags
+}
+
+// containersUsingImage returns the IDs of all containers, including stopped ones, created from the image.
+func containersUsingImage(ctx context.Context, client *client.Client, imageID string) ([]string, error) {
+	containers, err := client.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list Docker containers: %w", err)
+	}
+
+	containerIDs := make([]string, 0)
+	for _, c := range containers {
+		if c.ImageID == imageID {
+			containerIDs = append(containerIDs, c.ID[:12])
+		}
+	}
+	return containerIDs, nil
+}
+
 func fetchLocalImages(ctx context.Context, data *Data, client *client.Client) error {
 	images, err := client.ImageList(ctx, image.ListOptions{All: false})
 	if err != nil {
@@ -393,23 +548,35 @@ func legacyBuildDockerImage(ctx context.Context, rawBuild map[string]interface{}
 
 	buildContext := rawBuild["context"].(string)
 
-	// Each build must have its own session. Never reuse buildKitSession!
-	buildKitSession, sessionDone := enableBuildKitIfSupported(ctx, client, &buildOptions)
-	// If Buildkit is enabled, try to parse and use secrets if present.
-	if buildKitSession != nil {
-		if secretsRaw, secretsDefined := rawBuild["secrets"]; secretsDefined {
-			parsedSecrets := parseBuildSecrets(secretsRaw)
+	// The secrets and ssh agents are only used if Buildkit is enabled. They have to be
+	// attached to the session before it is started.
+	var attachables []session.Attachable
+	if secretsRaw, secretsDefined := rawBuild["secrets"]; secretsDefined {
+		parsedSecrets := parseBuildSecrets(secretsRaw)
 
-			store, err := secretsprovider.NewStore(parsedSecrets)
-			if err != nil {
-				return err
-			}
+		store, err := secretsprovider.NewStore(parsedSecrets)
+		if err != nil {
+			return err
+		}
+
+		attachables = append(attachables, secretsprovider.NewSecretProvider(store))
+	}
+	if sshRaw, sshDefined := rawBuild["ssh"].([]interface{}); sshDefined && len(sshRaw) > 0 {
+		agentConfigs, err := parseBuildSSH(sshRaw)
+		if err != nil {
+			return err
+		}
 
-			provider := secretsprovider.NewSecretProvider(store)
-			buildKitSession.Allow(provider)
+		provider, err := sshprovider.NewSSHAgentProvider(agentConfigs)
+		if err != nil {
+			return fmt.Errorf("invalid ssh configuration: %w", err)
 		}
+		attachables = append(attachables, provider)
 	}
 
+	// Each build must have its own session. Never reuse buildKitSession!
+	buildKitSession, sessionDone := enableBuildKitIfSupported(ctx, client, &buildOptions, attachables...)
+
 	buildCtx, relDockerfile, err := prepareBuildContext(buildContext, buildOptions.Dockerfile)
 	if err != nil {
 		if buildKitSession != nil {
@@ -457,6 +624,7 @@ func enableBuildKitIfSupported(
 	ctx context.Context,
 	client *client.Client,
 	buildOptions *dockerBuildTypes.ImageBuildOptions,
+	attachables ...session.Attachable,
 ) (*session.Session, chan struct{}) {
 	dockerClientVersion := client.ClientVersion()
 	log.Printf("[DEBUG] DockerClientVersion: %v, minBuildKitDockerVersion: %v\n", dockerClientVersion, minBuildkitDockerVersion)
@@ -465,6 +633,9 @@ func enableBuildKitIfSupported(
 		sessionKey := fmt.Sprintf("docker-provider-%d", rand.Int63())
 		log.Printf("[DEBUG] Creating BuildKit session with key: %s", sessionKey)
 		s, _ := session.NewSession(ctx, sessionKey)
+		for _, attachable := range attachables {
+			s.Allow(attachable)
+		}
 		dialSession := func(ctx context.Context, proto string, meta map[string][]string) (net.Conn, error) {
 			return client.DialHijack(ctx, "/session", proto, meta)
 		}
@@ -563,13 +734,11 @@ func prepareBuildContext(specifiedContext string, specifiedDockerfile string) (i
 		}
 		defer dockerfileCtx.Close() //nolint:errcheck
 	}
-	excludes, err := build.ReadDockerignore(contextDir)
+	// specifiedDockerfile = archive.CanonicalTarNameForPath(specifiedDockerfile)
+	excludes, err := readBuildContextExcludes(contextDir, specifiedDockerfile)
 	if err != nil {
 		return nil, "", err
 	}
-
-	// specifiedDockerfile = archive.CanonicalTarNameForPath(specifiedDockerfile)
-	excludes = build.TrimBuildFilesFromExcludes(excludes, specifiedDockerfile, false)
 	log.Printf("[DEBUG] Excludes: %v", excludes)
 	buildCtx := getBuildContext(contextDir, excludes)
 
@@ -651,3 +820,60 @@ func parseBuildSecrets(secretsRaw interface{}) []secretsprovider.Source {
 
 	return secrets
 }
+
+// parseBuildSSH maps the ssh entries of the build block to agent configs. A leading `~`
+// in the paths is expanded to the home directory.
+func parseBuildSSH(sshRaw []interface{}) ([]sshprovider.AgentConfig, error) {
+	agentConfigs := make([]sshprovider.AgentConfig, 0, len(sshRaw))
+	for _, option := range sshRaw {
+		sshEntry, ok := option.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		agentConfig := sshprovider.AgentConfig{ID: "default"}
+		if id, _ := sshEntry["id"].(string); id != "" {
+			agentConfig.ID = id
+		}
+		paths, _ := sshEntry["paths"].([]interface{})
+		for _, rawPath := range paths {
+			path, err := homedir.Expand(rawPath.(string))
+			if err != nil {
+				return nil, fmt.Errorf("error expanding ssh path %s: %w", rawPath, err)
+			}
+			agentConfig.Paths = append(agentConfig.Paths, path)
+		}
+		agentConfigs = append(agentConfigs, agentConfig)
+	}
+
+	return agentConfigs, nil
+}
+
+// validateBuildSSH validates the ssh entries of the build block at plan time by loading the
+// agent sockets and keys in the same way as the build does. Existing resources are only
+// validated if the build changes, as the sockets and keys are not needed otherwise.
+func validateBuildSSH(d *schema.ResourceDiff) error {
+	if d.Id() != "" && !d.HasChange("build") {
+		return nil
+	}
+	if !d.NewValueKnown("build") {
+		return nil
+	}
+
+	for _, rawBuild := range d.Get("build").(*schema.Set).List() {
+		sshRaw, _ := rawBuild.(map[string]interface{})["ssh"].([]interface{})
+		if len(sshRaw) == 0 {
+			continue
+		}
+
+		agentConfigs, err := parseBuildSSH(sshRaw)
+		if err != nil {
+			return err
+		}
+		if _, err := sshprovider.NewSSHAgentProvider(agentConfigs); err != nil {
+			return fmt.Errorf("invalid ssh configuration in build: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,11 @@ package provider
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"log"
 	"os"
@@ -22,6 +27,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/moby/buildkit/session/sshforward/sshprovider"
 )
 
 var contentDigestRegexp = regexp.MustCompile(`\A[A-Za-z0-9_\+\.-]+:[A-Fa-f0-9]+\z`)
@@ -951,3 +957,356 @@ func TestResolveDockerfilePath(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateBuildContextHash(t *testing.T) {
+	contextDir := t.TempDir()
+
+	testFiles := map[string]string{
+		"Dockerfile":        "FROM alpine:latest\nCOPY src /src\n",
+		".dockerignore":     "ignored\n",
+		"src/main.go":       "package main\n",
+		"ignored/build.log": "build log\n",
+	}
+	for path, content := range testFiles {
+		absPath := filepath.Join(contextDir, path)
+		if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", path, err)
+		}
+		if err := os.WriteFile(absPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", path, err)
+		}
+	}
+
+	rawBuild := map[string]interface{}{
+		"context":    contextDir,
+		"dockerfile": "Dockerfile",
+		"build_args": map[string]interface{}{"foo": "bar"},
+	}
+	hash := func() string {
+		h, err := calculateBuildContextHash(context.Background(), rawBuild)
+		if err != nil {
+			t.Fatalf("Failed to calculate build context hash: %v", err)
+		}
+		return h
+	}
+
+	initialHash := hash()
+	if initialHash != hash() {
+		t.Fatalf("Expected the build context hash to be stable")
+	}
+
+	if err := os.WriteFile(filepath.Join(contextDir, "ignored", "build.log"), []byte("another build log\n"), 0644); err != nil {
+		t.Fatalf("Failed to update ignored file: %v", err)
+	}
+	if h := hash(); h != initialHash {
+		t.Errorf("Expected changes to files excluded by .dockerignore to not change the hash, got %s and %s", initialHash, h)
+	}
+
+	if err := os.WriteFile(filepath.Join(contextDir, "src", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to update source file: %v", err)
+	}
+	sourceChangedHash := hash()
+	if sourceChangedHash == initialHash {
+		t.Errorf("Expected changes to files in the build context to change the hash")
+	}
+
+	rawBuild["build_args"] = map[string]interface{}{"foo": "baz"}
+	if h := hash(); h == sourceChangedHash {
+		t.Errorf("Expected changes to the build args to change the hash")
+	}
+}
+
+func TestFlattenBuildResult(t *testing.T) {
+	if got := flattenBuildResult(nil); len(got) != 0 {
+		t.Errorf("Expected no build metadata for the legacy builder, got %v", got)
+	}
+
+	got := flattenBuildResult(&buildResult{
+		imageDigest:   "sha256:1111",
+		configDigest:  "sha256:2222",
+		buildRef:      "default/default/abcdef",
+		warnings:      []string{"JSONArgsRecommended: JSON arguments recommended for CMD"},
+		builderName:   "default",
+		builderSource: SourceContext,
+	})
+	expected := []interface{}{
+		map[string]interface{}{
+			"image_digest":   "sha256:1111",
+			"config_digest":  "sha256:2222",
+			"build_ref":      "default/default/abcdef",
+			"warnings":       []string{"JSONArgsRecommended: JSON arguments recommended for CMD"},
+			"builder_name":   "default",
+			"builder_source": "docker-context",
+		},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestAccDockerImage_buildSSHInvalid(t *testing.T) {
+	missingKey := filepath.Join(t.TempDir(), "id_missing")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_image", "testDockerImageBuildSSHInvalid"), missingKey),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`invalid ssh configuration in build`),
+			},
+		},
+	})
+}
+
+func TestParseBuildSSH(t *testing.T) {
+	keyDir := t.TempDir()
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	keyPath := filepath.Join(keyDir, "id_ecdsa")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	invalidKeyPath := filepath.Join(keyDir, "id_invalid")
+	if err := os.WriteFile(invalidKeyPath, []byte("not a key"), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	sshRaw := []interface{}{
+		map[string]interface{}{"id": "github", "paths": []interface{}{keyPath}},
+		map[string]interface{}{"id": "", "paths": []interface{}{}},
+	}
+	agentConfigs, err := parseBuildSSH(sshRaw)
+	if err != nil {
+		t.Fatalf("Unable to parse ssh entries: %v", err)
+	}
+	if len(agentConfigs) != 2 {
+		t.Fatalf("Expected 2 agent configs, got %d", len(agentConfigs))
+	}
+	if agentConfigs[0].ID != "github" || !reflect.DeepEqual(agentConfigs[0].Paths, []string{keyPath}) {
+		t.Errorf("Unexpected agent config %+v", agentConfigs[0])
+	}
+	if agentConfigs[1].ID != "default" || len(agentConfigs[1].Paths) != 0 {
+		t.Errorf("Expected the default agent, got %+v", agentConfigs[1])
+	}
+
+	contextDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(contextDir, "Dockerfile"), []byte("FROM scratch\n"), 0644); err != nil {
+		t.Fatalf("Failed to create Dockerfile: %v", err)
+	}
+	options, err := mapBuildAttributesToBuildOptions(map[string]interface{}{
+		"context":    contextDir,
+		"dockerfile": "Dockerfile",
+		"tag":        []interface{}{},
+		"builder":    "default",
+		"ssh":        sshRaw,
+	}, "tftest-ssh:latest", nil)
+	if err != nil {
+		t.Fatalf("Unable to map build attributes: %v", err)
+	}
+	expectedSSH := []string{"github=" + keyPath, "default"}
+	if !reflect.DeepEqual(options.ssh, expectedSSH) {
+		t.Errorf("Expected ssh %v, got %v", expectedSSH, options.ssh)
+	}
+
+	if _, err := sshprovider.NewSSHAgentProvider(agentConfigs[:1]); err != nil {
+		t.Errorf("Expected the key to be valid: %v", err)
+	}
+	invalidConfigs, err := parseBuildSSH([]interface{}{
+		map[string]interface{}{"id": "github", "paths": []interface{}{invalidKeyPath}},
+	})
+	if err != nil {
+		t.Fatalf("Unable to parse ssh entries: %v", err)
+	}
+	if _, err := sshprovider.NewSSHAgentProvider(invalidConfigs); err == nil {
+		t.Errorf("Expected an invalid key to be rejected")
+	}
+}
+
+func TestAccDockerImage_destroyKeepsSharedImage(t *testing.T) {
+	ctx := context.Background()
+	sharedTag := "tf-test-shared-image:latest"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			defer exec.Command("docker", "rmi", sharedTag).Run() // nolint:errcheck
+
+			client, err := testAccProvider.Meta().(*ProviderConfig).MakeClient(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("failed to create Docker client: %w", err)
+			}
+			sharedImage, err := client.ImageInspect(ctx, sharedTag)
+			if err != nil {
+				return fmt.Errorf("expected the image to be kept for tag %s: %w", sharedTag, err)
+			}
+			for _, repoTag := range sharedImage.RepoTags {
+				if repoTag == "busybox:1.36.1" {
+					return fmt.Errorf("expected tag busybox:1.36.1 to be removed, got tags %v", sharedImage.RepoTags)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: loadTestConfiguration(t, RESOURCE, "docker_image", "testDockerImageSharedTag"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("docker_image.foo", "image_id"),
+					func(*terraform.State) error {
+						return exec.Command("docker", "tag", "busybox:1.36.1", sharedTag).Run()
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestOtherImageTags(t *testing.T) {
+	repoTags := []string{"alpine:latest", "alpine:3.20", "registry.example.com/team/alpine:3.20", "<none>:<none>"}
+
+	cases := []struct {
+		imageName string
+		expected  []string
+	}{
+		{imageName: "alpine", expected: []string{"alpine:3.20", "registry.example.com/team/alpine:3.20"}},
+		{imageName: "docker.io/library/alpine:3.20", expected: []string{"alpine:latest", "registry.example.com/team/alpine:3.20"}},
+		{imageName: "alpine:3.20@sha256:beefdbd8a1da6d2915566fde36db9db0b524eb737fc57cd1367effd16dc0d06d", expected: []string{"alpine:latest", "registry.example.com/team/alpine:3.20"}},
+		{imageName: "alpine@sha256:beefdbd8a1da6d2915566fde36db9db0b524eb737fc57cd1367effd16dc0d06d", expected: []string{"alpine:latest", "alpine:3.20", "registry.example.com/team/alpine:3.20"}},
+	}
+	for _, c := range cases {
+		t.Run(c.imageName, func(t *testing.T) {
+			if otherTags := otherImageTags(c.imageName, repoTags); !reflect.DeepEqual(otherTags, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, otherTags)
+			}
+		})
+	}
+}
+
+func TestAccDockerImage_sourceArchive(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "busybox.tar")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			if output, err := exec.Command("docker", "pull", "busybox:1.36.1").CombinedOutput(); err != nil {
+				t.Fatalf("failed to pull image: %v: %s", err, output)
+			}
+			if output, err := exec.Command("docker", "save", "-o", archivePath, "busybox:1.36.1").CombinedOutput(); err != nil {
+				t.Fatalf("failed to save image: %v: %s", err, output)
+			}
+		},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_image", "testDockerImageSourceArchive"), archivePath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("docker_image.foo", "image_id", contentDigestRegexp),
+					resource.TestMatchResourceAttr("docker_image.foo", "source_archive.0.checksum", regexp.MustCompile(`^[a-f0-9]{64}$`)),
+				),
+			},
+			{
+				// the image is reloaded after it was removed out of band
+				PreConfig: func() {
+					if output, err := exec.Command("docker", "rmi", "tf-test-source-archive:latest").CombinedOutput(); err != nil {
+						t.Fatalf("failed to remove image: %v: %s", err, output)
+					}
+				},
+				Config: fmt.Sprintf(loadTestConfiguration(t, RESOURCE, "docker_image", "testDockerImageSourceArchive"), archivePath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("docker_image.foo", "image_id", contentDigestRegexp),
+				),
+			},
+		},
+	})
+}
+
+func TestParseImageLoadOutput(t *testing.T) {
+	output := strings.Join([]string{
+		`{"status":"Loading layer","progressDetail":{"current":32768,"total":4495360},"id":"65014c70e84b"}`,
+		`{"stream":"Loaded image: busybox:1.36.1\n"}`,
+		`{"stream":"Loaded image ID: sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741\n"}`,
+	}, "\n")
+
+	loadedImages, err := parseImageLoadOutput(strings.NewReader(output))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"busybox:1.36.1", "sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741"}
+	if !reflect.DeepEqual(loadedImages, expected) {
+		t.Errorf("expected %v, got %v", expected, loadedImages)
+	}
+
+	if _, err := parseImageLoadOutput(strings.NewReader(`{"errorDetail":{"message":"unexpected EOF"},"error":"unexpected EOF"}`)); err == nil || err.Error() != "unexpected EOF" {
+		t.Errorf("expected the error of the image load, got %v", err)
+	}
+}
+
+func TestCalculateSourceArchiveChecksum(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	archivePath := filepath.Join(dir, "image.tar")
+	if err := os.WriteFile(archivePath, []byte("archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	checksum, err := calculateSourceArchiveChecksum(ctx, archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sha256 of "archive"
+	if checksum != "0eb3e36bfb24dcd9bb1d1bece1531216b59539a8fde17ee80224af0653c92aa3" {
+		t.Errorf("unexpected checksum %s", checksum)
+	}
+
+	layoutDir := filepath.Join(dir, "layout")
+	if err := os.MkdirAll(filepath.Join(layoutDir, "blobs", "sha256"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(layoutDir, "index.json"), []byte(`{"schemaVersion":2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	layoutChecksum, err := calculateSourceArchiveChecksum(ctx, layoutDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(layoutDir, "blobs", "sha256", "abc"), []byte("blob"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	changedChecksum, err := calculateSourceArchiveChecksum(ctx, layoutDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changedChecksum == layoutChecksum {
+		t.Error("expected the checksum of the directory to change when a file is added")
+	}
+
+	if _, err := calculateSourceArchiveChecksum(ctx, filepath.Join(dir, "missing.tar")); err == nil {
+		t.Error("expected an error for a missing archive")
+	}
+}
+
+func TestImageMatchesDigest(t *testing.T) {
+	imageInspect := image.InspectResponse{
+		ID:          "sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741",
+		RepoDigests: []string{"busybox@sha256:7edf5efe6b86dbf01ccc3c76b32a37a8e23b84e6bad81ce8ae8c221fa456fda8"},
+	}
+
+	for digest, expected := range map[string]bool{
+		"sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741": true,
+		"sha256:7edf5efe6b86dbf01ccc3c76b32a37a8e23b84e6bad81ce8ae8c221fa456fda8": true,
+		"sha256:0000000000000000000000000000000000000000000000000000000000000000": false,
+	} {
+		if matches := imageMatchesDigest(imageInspect, digest); matches != expected {
+			t.Errorf("expected %t for %s, got %t", expected, digest, matches)
+		}
+	}
+}
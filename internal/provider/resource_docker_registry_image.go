@@ -20,6 +20,7 @@ func resourceDockerRegistryImage() *schema.Resource {
 		ReadContext:   resourceDockerRegistryImageRead,
 		DeleteContext: resourceDockerRegistryImageDelete,
 		UpdateContext: resourceDockerRegistryImageUpdate,
+		CustomizeDiff: resourceDockerRegistryImageCustomizeDiff,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(dockerRegistryImageCreateDefaultTimeout),
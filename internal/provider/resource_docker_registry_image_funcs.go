@@ -51,7 +51,7 @@ func resourceDockerRegistryImageCreate(ctx context.Context, d *schema.ResourceDa
 	log.Printf("[DEBUG] Creating docker image %s", name)
 	if value, ok := d.GetOk("build"); ok {
 		for _, rawBuild := range value.(*schema.Set).List() {
-			shouldReturn, d1 := buildImage(ctx, rawBuild, client, name)
+			_, shouldReturn, d1 := buildImage(ctx, rawBuild, client, name)
 			if shouldReturn {
 				return d1
 			}
@@ -144,6 +144,10 @@ func resourceDockerRegistryImageDelete(ctx context.Context, d *schema.ResourceDa
 	return nil
 }
 
+func resourceDockerRegistryImageCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	return validateBuildSSH(d)
+}
+
 func resourceDockerRegistryImageUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	return resourceDockerRegistryImageRead(ctx, d, meta)
 }
@@ -0,0 +1 @@
+data "docker_build_cache" "daemon" {}
@@ -0,0 +1,3 @@
+data "docker_build_cache" "daemon" {
+  filter = ["type=regular"]
+}
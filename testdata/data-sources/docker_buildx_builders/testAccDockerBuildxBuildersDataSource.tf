@@ -0,0 +1,11 @@
+resource "docker_buildx_builder" "foo" {
+  name = "foo-builders"
+  docker_container {
+    image = "moby/buildkit:v0.22.0"
+  }
+  bootstrap = true
+}
+
+data "docker_buildx_builders" "all" {
+  depends_on = [docker_buildx_builder.foo]
+}
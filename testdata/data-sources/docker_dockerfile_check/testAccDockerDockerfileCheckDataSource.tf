@@ -0,0 +1,3 @@
+data "docker_dockerfile_check" "foo" {
+  context = "%s"
+}
@@ -0,0 +1,8 @@
+resource "docker_buildx_bake" "foo" {
+  files   = ["%s"]
+  targets = ["default"]
+
+  variables = {
+    DIST = "%s"
+  }
+}
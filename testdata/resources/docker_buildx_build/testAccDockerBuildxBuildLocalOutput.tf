@@ -0,0 +1,10 @@
+resource "docker_buildx_build" "foo" {
+  context    = "%s"
+  dockerfile = "Dockerfile"
+  target     = "artifact"
+
+  output {
+    type = "local"
+    dest = "%s"
+  }
+}
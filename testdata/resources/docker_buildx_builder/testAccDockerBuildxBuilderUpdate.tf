@@ -0,0 +1,10 @@
+resource "docker_buildx_builder" "foo" {
+  name           = "foo-update"
+  node           = "%s"
+  platform       = [%s]
+  buildkit_flags = "%s"
+  docker_container {
+    image = "moby/buildkit:v0.22.0"
+  }
+  bootstrap = true
+}
@@ -0,0 +1,11 @@
+resource "docker_image" "foo" {
+  name         = "busybox:1.35.0"
+  keep_locally = true
+}
+
+resource "docker_container" "foo" {
+  name          = "tf-test"
+  image         = docker_image.foo.image_id
+  command       = ["sh", "-c", "trap 'exit 0' TERM; while true; do sleep 1; done"]
+  desired_state = "%s"
+}
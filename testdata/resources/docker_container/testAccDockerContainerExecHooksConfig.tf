@@ -0,0 +1,23 @@
+resource "docker_image" "foo" {
+  name         = "busybox:1.35.0"
+  keep_locally = true
+}
+
+resource "docker_container" "foo" {
+  name          = "tf-test"
+  image         = docker_image.foo.image_id
+  command       = ["sh", "-c", "trap 'exit 0' TERM; while true; do sleep 1; done"]
+  desired_state = "%s"
+
+  post_start_exec {
+    command = ["sh", "-c", "echo \"$GREETING from $(pwd)\" >> /tmp/post_start"]
+    env     = ["GREETING=hello"]
+    workdir = "/tmp"
+  }
+
+  pre_stop_exec {
+    command            = ["sh", "-c", "touch /tmp/pre_stop; exit 3"]
+    success_exit_codes = [0, 3]
+    timeout            = "10s"
+  }
+}
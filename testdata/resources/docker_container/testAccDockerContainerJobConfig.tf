@@ -0,0 +1,21 @@
+resource "docker_image" "foo" {
+  name         = "busybox:1.35.0"
+  keep_locally = true
+}
+
+resource "docker_container" "foo" {
+  name    = "tf-test-job"
+  image   = docker_image.foo.image_id
+  command = ["sh", "-c", "echo migrating; echo migrated; exit %s"]
+  rm      = %s
+  logs    = true
+
+  job {
+    success_exit_codes = [0, 3]
+    timeout            = "%s"
+
+    rerun_on = {
+      migration = "%s"
+    }
+  }
+}
@@ -0,0 +1,35 @@
+resource "docker_image" "foo" {
+  name         = "nginx:latest"
+  keep_locally = true
+}
+
+resource "docker_container" "foo" {
+  name  = "tf-test"
+  image = docker_image.foo.image_id
+
+  ports {
+    internal = 80
+  }
+
+  readiness {
+    log {
+      pattern = "start worker processes?$"
+      timeout = "30s"
+    }
+    tcp {
+      port    = 80
+      timeout = "30s"
+    }
+    http {
+      port            = 80
+      path            = "/index.html"
+      expected_status = 200
+      timeout         = "30s"
+      interval        = "500ms"
+    }
+    exec {
+      command = ["test", "-f", "/usr/share/nginx/html/index.html"]
+      timeout = "30s"
+    }
+  }
+}
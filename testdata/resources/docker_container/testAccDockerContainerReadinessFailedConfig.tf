@@ -0,0 +1,18 @@
+resource "docker_image" "foo" {
+  name         = "busybox:1.35.0"
+  keep_locally = true
+}
+
+resource "docker_container" "foo" {
+  name    = "tf-test"
+  image   = docker_image.foo.image_id
+  command = ["sh", "-c", "echo still starting; sleep 300"]
+
+  readiness {
+    log {
+      pattern  = "ready to accept connections"
+      timeout  = "3s"
+      interval = "500ms"
+    }
+  }
+}
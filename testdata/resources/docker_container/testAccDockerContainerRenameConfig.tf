@@ -0,0 +1,16 @@
+resource "docker_image" "foo" {
+  name = "nginx:latest"
+}
+
+resource "docker_network" "test" {
+  name = "tf-test-rename"
+}
+
+resource "docker_container" "foo" {
+  name  = "%s"
+  image = docker_image.foo.image_id
+
+  networks_advanced {
+    name = docker_network.test.name
+  }
+}
@@ -0,0 +1,19 @@
+resource "docker_image" "foo" {
+  name         = "nginx:latest"
+  keep_locally = true
+}
+
+resource "docker_network" "test_network_1" {
+  name = "tftest-update-1"
+}
+
+resource "docker_network" "test_network_2" {
+  name = "tftest-update-2"
+}
+
+resource "docker_container" "foo" {
+  name  = "tf-test"
+  image = docker_image.foo.image_id
+
+%s
+}
@@ -0,0 +1,15 @@
+resource "docker_image" "foo" {
+  name = "nginx:latest"
+}
+
+resource "docker_container" "foo" {
+  name  = "tf-test-update-resources"
+  image = docker_image.foo.image_id
+
+  cpus        = %s
+  cpu_period  = %s
+  cpu_quota   = %s
+  memory      = %s
+  memory_swap = %s
+  pids_limit  = %s
+}
@@ -0,0 +1,21 @@
+resource "docker_image" "foo" {
+  name         = "busybox:1.35.0"
+  keep_locally = true
+}
+
+resource "docker_container" "foo" {
+  name    = "tf-test"
+  image   = docker_image.foo.image_id
+  command = ["sh", "-c", "while true; do sleep 1; done"]
+
+  upload {
+    source  = "%s"
+    file    = "/terraform/config"
+    exclude = ["*.bak"]
+  }
+
+  upload {
+    source_archive = "%s"
+    file           = "/terraform/archive"
+  }
+}
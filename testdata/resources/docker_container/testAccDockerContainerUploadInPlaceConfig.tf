@@ -0,0 +1,16 @@
+resource "docker_image" "foo" {
+  name         = "busybox:1.35.0"
+  keep_locally = true
+}
+
+resource "docker_container" "foo" {
+  name    = "tf-test"
+  image   = docker_image.foo.image_id
+  command = ["sh", "-c", "trap 'cp /terraform/config /tmp/reloaded' HUP; while true; do sleep 1; done"]
+
+  upload {
+    content          = "%s"
+    file             = "/terraform/config"
+    signal_on_change = "SIGHUP"
+  }
+}
@@ -0,0 +1,11 @@
+resource "docker_image" "test" {
+  name = "tftest-ssh:latest"
+  build {
+    context = "."
+
+    ssh {
+      id    = "github"
+      paths = ["%s"]
+    }
+  }
+}
@@ -0,0 +1,3 @@
+resource "docker_image" "foo" {
+  name = "busybox:1.36.1"
+}
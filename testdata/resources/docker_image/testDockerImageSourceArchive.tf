@@ -0,0 +1,7 @@
+resource "docker_image" "foo" {
+  name = "tf-test-source-archive:latest"
+
+  source_archive {
+    path = "%s"
+  }
+}